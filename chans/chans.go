@@ -1,10 +1,14 @@
 package chans
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/kendfss/but"
+	"github.com/kendfss/iters/slices"
 	"github.com/kendfss/rules"
 )
 
@@ -49,6 +53,33 @@ func Process[T any](c chan T) {
 	}
 }
 
+// DrainN receives and discards up to n values from src, returning how many it
+// received. This is less than n iff src closed first
+func DrainN[T any](n int, src <-chan T) (count int) {
+	for ; count < n; count++ {
+		if _, ok := <-src; !ok {
+			break
+		}
+	}
+	return
+}
+
+// DrainCtx receives and discards values from src until it closes or ctx is done,
+// returning how many it received and, in the latter case, ctx.Err()
+func DrainCtx[T any](ctx context.Context, src <-chan T) (count int, err error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		case _, ok := <-src:
+			if !ok {
+				return count, nil
+			}
+			count++
+		}
+	}
+}
+
 func Filter(ch chan bool) chan bool {
 	out := make(chan bool, DefaultCapacity)
 	go func() {
@@ -98,6 +129,23 @@ func RO[T any](c chan T) <-chan T {
 	return out
 }
 
+// Buffer forwards the values of src through a channel with room for size of them,
+// so that a bursty producer can run up to size values ahead of a slow consumer.
+// The output closes when src does. Non-positive sizes yield an unbuffered channel
+func Buffer[T any](size int, src <-chan T) <-chan T {
+	if size < 0 {
+		size = 0
+	}
+	out := make(chan T, size)
+	go func() {
+		defer close(out)
+		for x := range src {
+			out <- x
+		}
+	}()
+	return out
+}
+
 func Count[T any](c chan T) (out uint64) {
 	for range c {
 		out++
@@ -148,6 +196,19 @@ func Do[T any](f func(T), ch <-chan T) {
 	}()
 }
 
+// DoWait calls a function on every value of a channel, like Do, and returns a
+// channel which closes once the source has been drained and every call has returned
+func DoWait[T any](f func(T), ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range ch {
+			f(e)
+		}
+	}()
+	return done
+}
+
 // Cast calls a pure function on every value of a channel and returns a channel
 // containing all the results
 func Cast[I, O any](f func(I) O, ch <-chan I) chan O {
@@ -160,6 +221,27 @@ func Cast[I, O any](f func(I) O, ch <-chan I) chan O {
 	return out
 }
 
+// CastErr calls f on every value of src, sending its successful results to the
+// first channel and its errors to the second, in the order they occur. Both
+// close once src closes. Each send blocks until it is received, so both
+// channels must be consumed, concurrently, for either to make progress
+func CastErr[I, O any](f func(I) (O, error), src <-chan I) (<-chan O, <-chan error) {
+	out := make(chan O, DefaultCapacity)
+	errs := make(chan error, DefaultCapacity)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		for e := range src {
+			if v, err := f(e); err != nil {
+				errs <- err
+			} else {
+				out <- v
+			}
+		}
+	}()
+	return out, errs
+}
+
 func StepStr[T rules.Char](arg string) chan T {
 	out := make(chan T)
 	go func() {
@@ -321,3 +403,508 @@ func PredPutter[T any](dst chan T, pred func(T) bool) func(T) error {
 }
 
 var ErrUnsatisfied = but.New("Predicate was not satisfied")
+
+// Rotated re-emits the contents of src, index-shifted as though they sat on a torus
+// (see slices.Rotated). src must be finite: nothing is sent until it closes because
+// the whole stream has to be buffered before the first rotated value is known
+func Rotated[T any](steps int, src <-chan T) <-chan T {
+	out := make(chan T, DefaultCapacity)
+	go func() {
+		defer close(out)
+		var buf []T
+		for e := range src {
+			buf = append(buf, e)
+		}
+		if len(buf) == 0 {
+			return
+		}
+		steps %= len(buf)
+		if steps < 0 {
+			steps += len(buf)
+		}
+		for _, e := range buf[steps:] {
+			out <- e
+		}
+		for _, e := range buf[:steps] {
+			out <- e
+		}
+	}()
+	return out
+}
+
+// Interleave takes one value from each of srcs in turn, skipping those that have closed,
+// until all of them are closed. Unlike Chain, the order of the output is deterministic;
+// the price is that a slow source holds up the others
+func Interleave[T any](srcs ...<-chan T) <-chan T {
+	out := make(chan T, DefaultCapacity)
+	go func() {
+		defer close(out)
+		live := append([]<-chan T{}, srcs...)
+		for len(live) > 0 {
+			next := live[:0]
+			for _, src := range live {
+				if e, ok := <-src; ok {
+					out <- e
+					next = append(next, src)
+				}
+			}
+			live = next
+		}
+	}()
+	return out
+}
+
+// DedupWindow forwards the values of src, dropping any that are among the last
+// "window" distinct values it has forwarded. Unlike Compact, its memory is bounded
+// by the window, so it is safe on unbounded streams, but duplicates further apart
+// than the window are let through. A non-positive window forwards everything
+func DedupWindow[T comparable](window int, src <-chan T) <-chan T {
+	out := make(chan T, DefaultCapacity)
+	go func() {
+		defer close(out)
+		if window <= 0 {
+			for e := range src {
+				out <- e
+			}
+			return
+		}
+		seen := make(map[T]struct{}, window)
+		ring := make([]T, 0, window)
+		next := 0
+		for e := range src {
+			if _, ok := seen[e]; ok {
+				continue
+			}
+			if len(ring) < window {
+				ring = append(ring, e)
+			} else {
+				delete(seen, ring[next])
+				ring[next] = e
+				next = (next + 1) % window
+			}
+			seen[e] = struct{}{}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// Through composes two pipeline stages into one which feeds the output of f into g
+func Through[A, B, C any](f func(<-chan A) <-chan B, g func(<-chan B) <-chan C) func(<-chan A) <-chan C {
+	return func(src <-chan A) <-chan C {
+		return g(f(src))
+	}
+}
+
+// Pipeline composes any number of pipeline stages, of a single type, into one
+// which feeds its input through each of them in order.
+// The output closes when the last stage closes it, so every stage should close
+// its output once its input is closed. No stages yields the identity stage
+func Pipeline[T any](stages ...func(<-chan T) <-chan T) func(<-chan T) <-chan T {
+	return func(src <-chan T) <-chan T {
+		for _, stage := range stages {
+			src = stage(src)
+		}
+		return src
+	}
+}
+
+// Any reports whether any value received from src satisfies pred.
+// It returns as soon as one does, so src may be left undrained; callers are
+// responsible for stopping or draining its producer
+func Any[T any](pred func(T) bool, src <-chan T) bool {
+	for e := range src {
+		if pred(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether every value received from src satisfies pred.
+// It returns as soon as one does not, so src may be left undrained; callers are
+// responsible for stopping or draining its producer
+func All[T any](pred func(T) bool, src <-chan T) bool {
+	for e := range src {
+		if !pred(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sample drains src and returns min(k, n) of the n values it yielded, chosen
+// uniformly at random (Algorithm R). It makes a single pass over src and holds
+// no more than k values at a time. The order of the result is unspecified
+func Sample[T any](k int, src <-chan T) []T {
+	if k < 0 {
+		k = 0
+	}
+	out := make([]T, 0, k)
+	n := 0
+	for e := range src {
+		n++
+		if len(out) < k {
+			out = append(out, e)
+		} else if j := rand.Intn(n); j < k {
+			out[j] = e
+		}
+	}
+	return out
+}
+
+// Tag pairs each value of src with the given tag, so that its origin is known
+// after it has been merged with other streams (see MergeTagged)
+func Tag[T any, K comparable](tag K, src <-chan T) <-chan slices.LR[K, T] {
+	out := make(chan slices.LR[K, T], DefaultCapacity)
+	go func() {
+		defer close(out)
+		for e := range src {
+			out <- slices.LR[K, T]{Left: tag, Right: e}
+		}
+	}()
+	return out
+}
+
+// MergeTagged collects several tagged streams into one, closing it once they have all closed.
+// Like Chain, the order of the output is non-deterministic
+func MergeTagged[K comparable, T any](srcs ...<-chan slices.LR[K, T]) <-chan slices.LR[K, T] {
+	out := make(chan slices.LR[K, T], DefaultCapacity)
+	wg := new(sync.WaitGroup)
+	wg.Add(len(srcs))
+	for _, src := range srcs {
+		go func(src <-chan slices.LR[K, T]) {
+			defer wg.Done()
+			for e := range src {
+				out <- e
+			}
+		}(src)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+var ErrIdle = but.New("Source was idle for too long")
+
+// IdleTimeout forwards the values of src until it closes or until it has gone
+// longer than d without yielding a value, whichever comes first. In the latter
+// case ErrIdle is sent on the error channel before both channels close, and src
+// is left undrained. Time spent waiting for the output to be received does not count
+func IdleTimeout[T any](d time.Duration, src <-chan T) (<-chan T, <-chan error) {
+	out := make(chan T, DefaultCapacity)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case e, ok := <-src:
+				if !ok {
+					return
+				}
+				if !timer.Stop() {
+					<-timer.C
+				}
+				out <- e
+				timer.Reset(d)
+			case <-timer.C:
+				errs <- ErrIdle
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// CastOrdered works like Cast, but applies f to up to "workers" values of src
+// concurrently. Results are emitted in the order their inputs were received,
+// so one slow value holds back those behind it: no more than "workers" values are
+// taken from src before the oldest of them is emitted, which bounds the memory
+// spent on reordering. Non-positive worker counts are treated as 1
+func CastOrdered[I, O any](workers int, f func(I) O, src <-chan I) <-chan O {
+	if workers < 1 {
+		workers = 1
+	}
+	type job struct {
+		seq int
+		val I
+	}
+	type result struct {
+		seq int
+		val O
+	}
+	jobs := make(chan job)
+	results := make(chan result)
+	out := make(chan O, DefaultCapacity)
+	// a slot is held from when a value is dispatched until its result is emitted
+	slots := make(chan struct{}, workers)
+
+	go func() {
+		defer close(jobs)
+		seq := 0
+		for e := range src {
+			slots <- struct{}{}
+			jobs <- job{seq, e}
+			seq++
+		}
+	}()
+
+	wg := new(sync.WaitGroup)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- result{j.seq, f(j.val)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(out)
+		pending := make(map[int]O)
+		next := 0
+		for r := range results {
+			pending[r.seq] = r.val
+			for {
+				v, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				out <- v
+				<-slots
+				next++
+			}
+		}
+	}()
+	return out
+}
+
+// Last drains src and returns the last n values it yielded, or all of them if
+// it yielded fewer, in the order they were received. It holds no more than n
+// values at a time
+func Last[T any](n int, src <-chan T) []T {
+	if n <= 0 {
+		for range src {
+		}
+		return []T{}
+	}
+	ring := make([]T, 0, n)
+	next := 0
+	for e := range src {
+		if len(ring) < n {
+			ring = append(ring, e)
+		} else {
+			ring[next] = e
+			next = (next + 1) % n
+		}
+	}
+	return append(ring[next:], ring[:next]...)
+}
+
+// Head returns the first n values of src, or all of them if it closes sooner,
+// and then stops reading. Any producer still sending to src will block unless
+// it can be told to stop, so pair Head with a cancellable source (for example
+// one that also selects on a context) or drain the rest with Process
+func Head[T any](n int, src <-chan T) []T {
+	out := []T{}
+	for ; n > 0; n-- {
+		e, ok := <-src
+		if !ok {
+			break
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// CollectMap drains src into a map, indexing val(e) by key(e) for every value e.
+// When two values share a key, the one received last wins
+func CollectMap[T any, K comparable, V any](key func(T) K, val func(T) V, src <-chan T) map[K]V {
+	out := make(map[K]V)
+	for e := range src {
+		out[key(e)] = val(e)
+	}
+	return out
+}
+
+// Zip pairs the values of left and right, one from each per step, like slices.Zip2.
+// The output closes as soon as either source does. The other source is left
+// undrained, and a value already taken from it for an incomplete pair is dropped,
+// so any producer still sending to it should be stopped or drained (see Process)
+func Zip[L, R any](left <-chan L, right <-chan R) <-chan slices.LR[L, R] {
+	out := make(chan slices.LR[L, R], DefaultCapacity)
+	go func() {
+		defer close(out)
+		for {
+			l, ok := <-left
+			if !ok {
+				return
+			}
+			r, ok := <-right
+			if !ok {
+				return
+			}
+			out <- slices.LR[L, R]{Left: l, Right: r}
+		}
+	}()
+	return out
+}
+
+// Unzip splits a stream of pairs into a stream of their left values and another
+// of their right values, closing both once src closes. The outputs are
+// synchronized: each pair is delivered to both before the next is read, so both
+// must be consumed, concurrently, for either to make progress
+func Unzip[L, R any](src <-chan slices.LR[L, R]) (<-chan L, <-chan R) {
+	left := make(chan L, DefaultCapacity)
+	right := make(chan R, DefaultCapacity)
+	go func() {
+		defer close(left)
+		defer close(right)
+		for e := range src {
+			left <- e.Left
+			right <- e.Right
+		}
+	}()
+	return left, right
+}
+
+// Debounce collapses bursts of values from src: it emits a value only once d has
+// passed without src yielding another, and then only the latest one. If src closes
+// while a value is pending, that value is emitted before the output closes
+func Debounce[T any](d time.Duration, src <-chan T) <-chan T {
+	out := make(chan T, DefaultCapacity)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		if !timer.Stop() {
+			<-timer.C
+		}
+		var (
+			latest T
+			fire   <-chan time.Time // nil unless a value is pending
+		)
+		for {
+			select {
+			case e, ok := <-src:
+				if !ok {
+					if fire != nil {
+						out <- latest
+					}
+					return
+				}
+				if fire != nil && !timer.Stop() {
+					<-timer.C
+				}
+				latest, fire = e, timer.C
+				timer.Reset(d)
+			case <-fire:
+				fire = nil
+				out <- latest
+			}
+		}
+	}()
+	return out
+}
+
+// Flatten forwards the elements of each slice received from src, one at a time
+// and in order, closing once src closes
+func Flatten[T any](src <-chan []T) <-chan T {
+	out := make(chan T, DefaultCapacity)
+	go func() {
+		defer close(out)
+		for chunk := range src {
+			for _, e := range chunk {
+				out <- e
+			}
+		}
+	}()
+	return out
+}
+
+// GroupReduce drains src, folding each value into the accumulator of its key with f.
+// Each key's accumulator starts out as a fresh init(). It holds one accumulator
+// per distinct key until src closes, so keys should be drawn from a bounded set
+func GroupReduce[T any, K comparable, A any](key func(T) K, f func(A, T) A, init func() A, src <-chan T) map[K]A {
+	out := make(map[K]A)
+	for e := range src {
+		k := key(e)
+		acc, ok := out[k]
+		if !ok {
+			acc = init()
+		}
+		out[k] = f(acc, e)
+	}
+	return out
+}
+
+var ErrPanicked = but.New("Generator panicked")
+
+// Generate calls f repeatedly, sending each value it yields for as long as it
+// reports that there are more (the value returned alongside false is dropped).
+// A panic in f is treated as a transient failure and the call is retried, but
+// more than "retries" consecutive failures close the output, as does ctx being done.
+// see GenerateErr for generators which report their failures as errors
+func Generate[T any](ctx context.Context, f func() (T, bool), retries int) <-chan T {
+	out, _ := GenerateErr(ctx, func() (v T, more bool, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v", ErrPanicked, r)
+			}
+		}()
+		v, more = f()
+		return v, more, err
+	}, retries)
+	return out
+}
+
+// GenerateErr works like Generate, but f reports transient failures by returning
+// a non-nil error, in which case its other results are ignored. If the output
+// closes because f failed more than "retries" times in a row, or because ctx is
+// done, the last error or ctx.Err() is sent on the error channel before it closes
+func GenerateErr[T any](ctx context.Context, f func() (T, bool, error), retries int) (<-chan T, <-chan error) {
+	out := make(chan T, DefaultCapacity)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		failures := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+			v, more, err := f()
+			if err != nil {
+				failures++
+				if failures > retries {
+					errs <- err
+					return
+				}
+				continue
+			}
+			failures = 0
+			if !more {
+				return
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, errs
+}
@@ -0,0 +1,618 @@
+package chans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kendfss/iters/slices"
+	"github.com/stretchr/testify/assert"
+)
+
+// feed returns a channel which yields args and then closes
+func feed[T any](args ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, arg := range args {
+			out <- arg
+		}
+	}()
+	return out
+}
+
+// collect drains a channel into a slice
+func collect[T any](src <-chan T) (out []T) {
+	for e := range src {
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestRotated(t *testing.T) {
+	type test struct {
+		slice []int
+		want  []int
+		steps int
+	}
+	tests := []test{
+		{slice: []int{0, 1, 2}, steps: 0, want: []int{0, 1, 2}},
+		{slice: []int{0, 1, 2}, steps: 1, want: []int{1, 2, 0}},
+		{slice: []int{0, 1, 2}, steps: 2, want: []int{2, 0, 1}},
+		{slice: []int{0, 1, 2}, steps: 3, want: []int{0, 1, 2}},
+		{slice: []int{0, 1, 2}, steps: 4, want: []int{1, 2, 0}},
+		{slice: []int{0, 1, 2}, steps: -1, want: []int{2, 0, 1}},
+		{slice: []int{0, 1, 2}, steps: -3, want: []int{0, 1, 2}},
+		{slice: []int{0, 1, 2}, steps: -5, want: []int{1, 2, 0}},
+		{slice: nil, steps: 3, want: nil},
+	}
+	for i, test := range tests {
+		have := collect(Rotated(test.steps, feed(test.slice...)))
+		assert.Equal(t, test.want, have, "#%d: value failure", i)
+		assert.Equal(t, test.slice, collect(Rotated(-test.steps, feed(have...))), "#%d: round trip failure", i)
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	have := collect(Interleave(
+		feed(1, 4, 6, 8),
+		feed(2),
+		feed[int](),
+		feed(3, 5, 7),
+	))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, have)
+	assert.Equal(t, []int(nil), collect(Interleave[int]()))
+}
+
+func TestDedupWindow(t *testing.T) {
+	type test struct {
+		window int
+		arg    []int
+		want   []int
+	}
+	tests := []test{
+		{window: 2, arg: []int{1, 1, 2, 1, 3, 4, 1}, want: []int{1, 2, 3, 4, 1}},
+		{window: 1, arg: []int{1, 1, 2, 2, 1}, want: []int{1, 2, 1}},
+		{window: 3, arg: []int{1, 2, 3, 1, 2, 3, 4, 1}, want: []int{1, 2, 3, 4, 1}},
+		{window: 0, arg: []int{1, 1, 1}, want: []int{1, 1, 1}},
+		{window: 2, arg: nil, want: nil},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.want, collect(DedupWindow(test.window, feed(test.arg...))), "#%d", i)
+	}
+}
+
+// stage lifts a function into a pipeline stage which closes its output when its input closes
+func stage[I, O any](f func(I) O) func(<-chan I) <-chan O {
+	return func(src <-chan I) <-chan O {
+		out := make(chan O)
+		go func() {
+			defer close(out)
+			for e := range src {
+				out <- f(e)
+			}
+		}()
+		return out
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	double := stage(func(i int) int { return i * 2 })
+	inc := stage(func(i int) int { return i + 1 })
+	dedup := func(src <-chan int) <-chan int { return DedupWindow(2, src) }
+
+	have := collect(Pipeline(double, dedup, inc)(feed(1, 1, 2, 3, 3, 1)))
+	assert.Equal(t, []int{3, 5, 7, 3}, have)
+
+	assert.Equal(t, []int{1, 2}, collect(Pipeline[int]()(feed(1, 2))))
+	assert.Empty(t, collect(Pipeline(double, dedup, inc)(feed[int]())))
+}
+
+func TestThrough(t *testing.T) {
+	length := stage(func(s string) int { return len(s) })
+	even := stage(func(i int) bool { return i%2 == 0 })
+	rotate := func(src <-chan bool) <-chan bool { return Rotated(1, src) }
+
+	have := collect(Through(Through(length, even), rotate)(feed("a", "ab", "abc", "abcd")))
+	assert.Equal(t, []bool{true, false, true, false}, have)
+	assert.Empty(t, collect(Through(length, even)(feed[string]())))
+}
+
+// counter yields 0, 1, 2, ... until stop is closed, and records how many values were sent
+func counter(stop <-chan struct{}, sent *int64) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 0; ; i++ {
+			select {
+			case out <- i:
+				atomic.AddInt64(sent, 1)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func TestAnyAll(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	small := func(i int) bool { return i < 5 }
+
+	assert.True(t, Any(even, feed(1, 3, 4)))
+	assert.False(t, Any(even, feed(1, 3, 5)))
+	assert.False(t, Any(even, feed[int]()))
+	assert.True(t, All(even, feed(0, 2, 4)))
+	assert.False(t, All(even, feed(0, 2, 3)))
+	assert.True(t, All(even, feed[int]()))
+
+	t.Run("short-circuit", func(t *testing.T) {
+		var anySent, allSent int64
+		stop := make(chan struct{})
+		defer close(stop)
+		assert.True(t, Any(func(i int) bool { return i == 10 }, counter(stop, &anySent)))
+		assert.False(t, All(small, counter(stop, &allSent)))
+		assert.LessOrEqual(t, atomic.LoadInt64(&anySent), int64(12))
+		assert.LessOrEqual(t, atomic.LoadInt64(&allSent), int64(7))
+	})
+}
+
+func TestDrainN(t *testing.T) {
+	src := feed(1, 2, 3, 4, 5)
+	assert.Equal(t, 2, DrainN(2, src))
+	assert.Equal(t, 3, <-src)
+	assert.Equal(t, 2, DrainN(2, src))
+	assert.Equal(t, 0, DrainN(2, src))
+
+	assert.Equal(t, 3, DrainN(5, feed(1, 2, 3)))
+	assert.Equal(t, 0, DrainN(0, feed(1, 2, 3)))
+	assert.Equal(t, 0, DrainN(-1, feed(1, 2, 3)))
+}
+
+func TestDrainCtx(t *testing.T) {
+	n, err := DrainCtx(context.Background(), feed(1, 2, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	var sent int64
+	stop := make(chan struct{})
+	defer close(stop)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	n, err = DrainCtx(ctx, counter(stop, &sent))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, n > 0)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	n, err = DrainCtx(ctx, make(chan int))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, n)
+}
+
+func TestSample(t *testing.T) {
+	assert.Empty(t, Sample(0, feed(1, 2, 3)))
+	assert.Empty(t, Sample(-1, feed(1, 2, 3)))
+	assert.Empty(t, Sample(3, feed[int]()))
+	assert.ElementsMatch(t, []int{1, 2, 3}, Sample(5, feed(1, 2, 3)))
+
+	const runs, k, n = 5_000, 5, 50
+	counts := make([]int, n)
+	for i := 0; i < runs; i++ {
+		src := make(chan int)
+		go func() {
+			defer close(src)
+			for j := 0; j < n; j++ {
+				src <- j
+			}
+		}()
+		have := Sample(k, src)
+		assert.Len(t, have, k)
+		for _, e := range have {
+			counts[e]++
+		}
+	}
+	for e, c := range counts {
+		assert.InDelta(t, float64(k)/n, float64(c)/runs, 0.03, "selection frequency of %d", e)
+	}
+}
+
+func TestMergeTagged(t *testing.T) {
+	have := collect(MergeTagged(
+		Tag("odd", feed(1, 3, 5)),
+		Tag("even", feed(0, 2, 4, 6)),
+	))
+	assert.Len(t, have, 7)
+	tagged := map[string][]int{}
+	for _, pair := range have {
+		if pair.Right%2 == 0 {
+			assert.Equal(t, "even", pair.Left, "%d", pair.Right)
+		} else {
+			assert.Equal(t, "odd", pair.Left, "%d", pair.Right)
+		}
+		tagged[pair.Left] = append(tagged[pair.Left], pair.Right)
+	}
+	assert.Equal(t, []int{1, 3, 5}, tagged["odd"])
+	assert.Equal(t, []int{0, 2, 4, 6}, tagged["even"])
+
+	assert.Empty(t, collect(MergeTagged[int, int]()))
+}
+
+func TestIdleTimeout(t *testing.T) {
+	// the gaps below are far from d in either direction so that scheduling delays
+	// on a loaded machine cannot flip the outcome
+	const d = 100 * time.Millisecond
+	pace := func(gaps ...time.Duration) <-chan int {
+		out := make(chan int)
+		go func() {
+			defer close(out)
+			for i, gap := range gaps {
+				time.Sleep(gap)
+				out <- i
+			}
+		}()
+		return out
+	}
+
+	t.Run("steady", func(t *testing.T) {
+		gaps := slices.Repeat(d/10, 15) // longer than d in total, so the timer must be reset
+		out, errs := IdleTimeout(d, pace(gaps...))
+		assert.Equal(t, slices.Upton[int](len(gaps)), collect(out))
+		assert.NoError(t, <-errs)
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		out, errs := IdleTimeout(d, pace(0, d/10, 5*d, 0))
+		assert.Equal(t, []int{0, 1}, collect(out))
+		assert.ErrorIs(t, <-errs, ErrIdle)
+		_, ok := <-errs
+		assert.False(t, ok)
+	})
+
+	t.Run("slow consumer", func(t *testing.T) {
+		out, errs := IdleTimeout(d, feed(1, 2))
+		time.Sleep(3 * d)
+		assert.Equal(t, []int{1, 2}, collect(out))
+		assert.NoError(t, <-errs)
+	})
+}
+
+func TestCastOrdered(t *testing.T) {
+	args := make([]int, 50)
+	want := make([]int, len(args))
+	for i := range args {
+		args[i] = i
+		want[i] = i * i
+	}
+	var running, peak int64
+	square := func(i int) int {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration((i*7)%5) * time.Millisecond)
+		atomic.AddInt64(&running, -1)
+		return i * i
+	}
+
+	assert.Equal(t, want, collect(CastOrdered(4, square, feed(args...))))
+	assert.LessOrEqual(t, atomic.LoadInt64(&peak), int64(4))
+	assert.Equal(t, want[:5], collect(CastOrdered(0, square, feed(args[:5]...))))
+	assert.Empty(t, collect(CastOrdered(4, square, feed[int]())))
+
+	t.Run("slow head", func(t *testing.T) {
+		const workers = 4
+		var started int64
+		release := make(chan struct{})
+		slowHead := func(i int) int {
+			atomic.AddInt64(&started, 1)
+			if i == 0 {
+				<-release
+			}
+			return i * i
+		}
+		out := CastOrdered(workers, slowHead, feed(args...))
+		time.Sleep(20 * time.Millisecond)
+		assert.LessOrEqual(t, atomic.LoadInt64(&started), int64(workers), "values were taken from src while the head was stuck")
+		close(release)
+		assert.Equal(t, want, collect(out))
+	})
+}
+
+func TestBuffer(t *testing.T) {
+	const bursts, burst = 4, 8
+	src := make(chan int)
+	go func() {
+		defer close(src)
+		for i := 0; i < bursts; i++ {
+			for j := 0; j < burst; j++ {
+				src <- i*burst + j
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	out := Buffer(burst, src)
+	assert.Equal(t, burst, cap(out))
+	var have []int
+	for e := range out {
+		time.Sleep(100 * time.Microsecond)
+		have = append(have, e)
+	}
+	want := make([]int, bursts*burst)
+	for i := range want {
+		want[i] = i
+	}
+	assert.Equal(t, want, have)
+
+	assert.Equal(t, 0, cap(Buffer(-1, feed(1))))
+	assert.Equal(t, []int{1, 2}, collect(Buffer(0, feed(1, 2))))
+}
+
+func TestLast(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, Last(3, feed(1, 2)))
+	assert.Equal(t, []int{1, 2, 3}, Last(3, feed(1, 2, 3)))
+	assert.Equal(t, []int{5, 6, 7}, Last(3, feed(1, 2, 3, 4, 5, 6, 7)))
+	assert.Equal(t, []int{7}, Last(1, feed(1, 2, 3, 4, 5, 6, 7)))
+	assert.Empty(t, Last(3, feed[int]()))
+
+	src := feed(1, 2, 3)
+	assert.Empty(t, Last(0, src))
+	_, ok := <-src
+	assert.False(t, ok, "src should be drained")
+}
+
+func TestHead(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3}, Head(3, Buffer(10, feed(1, 2, 3, 4, 5, 6, 7, 8, 9, 10))))
+	assert.Equal(t, []int{1, 2}, Head(3, feed(1, 2)))
+	assert.Empty(t, Head(0, Buffer(2, feed(1, 2))))
+	assert.Empty(t, Head(3, feed[int]()))
+
+	t.Run("no leak", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		var sent int64
+		stop := make(chan struct{})
+		src := counter(stop, &sent)
+		assert.Equal(t, []int{0, 1, 2}, Head(3, src))
+		close(stop)
+		for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), before, "producer leaked")
+	})
+}
+
+func TestDoWait(t *testing.T) {
+	calls, sum := 0, 0
+	<-DoWait(func(i int) {
+		time.Sleep(time.Millisecond)
+		calls++
+		sum += i
+	}, feed(1, 2, 3, 4, 5))
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, 15, sum)
+
+	_, ok := <-DoWait(func(int) { t.Error("f called on empty source") }, feed[int]())
+	assert.False(t, ok)
+}
+
+func TestCollectMap(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+	id := func(u user) int { return u.id }
+	name := func(u user) string { return u.name }
+
+	have := CollectMap(id, name, feed(user{1, "ann"}, user{2, "bob"}, user{3, "cat"}))
+	assert.Equal(t, map[int]string{1: "ann", 2: "bob", 3: "cat"}, have)
+
+	have = CollectMap(id, name, feed(user{1, "ann"}, user{2, "bob"}, user{1, "amy"}))
+	assert.Equal(t, map[int]string{1: "amy", 2: "bob"}, have, "the last value should win")
+
+	assert.Empty(t, CollectMap(id, name, feed[user]()))
+}
+
+func TestZip(t *testing.T) {
+	pair := func(l int, r string) slices.LR[int, string] { return slices.LR[int, string]{Left: l, Right: r} }
+
+	have := collect(Zip(feed(1, 2, 3), feed("a", "b", "c")))
+	assert.Equal(t, []slices.LR[int, string]{pair(1, "a"), pair(2, "b"), pair(3, "c")}, have)
+
+	right := feed("a", "b", "c", "d")
+	have = collect(Zip(feed(1, 2), right))
+	assert.Equal(t, []slices.LR[int, string]{pair(1, "a"), pair(2, "b")}, have)
+	assert.Equal(t, []string{"c", "d"}, collect(right), "the longer source should be left undrained")
+
+	left := feed(1, 2, 3, 4)
+	have = collect(Zip(left, feed("a")))
+	assert.Equal(t, []slices.LR[int, string]{pair(1, "a")}, have)
+	assert.Equal(t, []int{3, 4}, collect(left), "only the value of the incomplete pair should be dropped")
+
+	assert.Empty(t, collect(Zip(feed[int](), feed[string]())))
+}
+
+func TestUnzip(t *testing.T) {
+	ints, strs := []int{1, 2, 3}, []string{"a", "b", "c"}
+	left, right := Unzip(Zip(feed(ints...), feed(strs...)))
+
+	var haveStrs []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		haveStrs = collect(right)
+	}()
+	haveInts := collect(left)
+	<-done
+	assert.Equal(t, ints, haveInts)
+	assert.Equal(t, strs, haveStrs)
+
+	left, right = Unzip(feed[slices.LR[int, string]]())
+	_, ok := <-left
+	assert.False(t, ok)
+	_, ok = <-right
+	assert.False(t, ok)
+}
+
+func TestDebounce(t *testing.T) {
+	// the gaps below are far from d in either direction so that scheduling delays
+	// on a loaded machine cannot flip the outcome
+	const d = 100 * time.Millisecond
+	bursts := func(bursts ...[]int) <-chan int {
+		out := make(chan int)
+		go func() {
+			defer close(out)
+			for _, burst := range bursts {
+				for _, e := range burst {
+					out <- e
+					time.Sleep(d / 10)
+				}
+				time.Sleep(5 * d)
+			}
+		}()
+		return out
+	}
+
+	have := collect(Debounce(d, bursts([]int{0, 1, 2, 3, 4}, []int{5, 6, 7})))
+	assert.Equal(t, []int{4, 7}, have, "only the last value of each burst should be emitted")
+
+	have = collect(Debounce(time.Hour, feed(1, 2, 3)))
+	assert.Equal(t, []int{3}, have, "the pending value should be emitted when the source closes")
+
+	assert.Empty(t, collect(Debounce(d, feed[int]())))
+}
+
+func TestFlatten(t *testing.T) {
+	chunks := [][]int{{1, 2}, {}, {3}, nil, {4, 5, 6}}
+	assert.Equal(t, slices.Chain(chunks...), collect(Flatten(feed(chunks...))))
+	assert.Empty(t, collect(Flatten(feed[[]int]())))
+	assert.Empty(t, collect(Flatten(feed([]int{}, nil))))
+}
+
+func TestCastErr(t *testing.T) {
+	errOdd := errors.New("odd")
+	half := func(i int) (int, error) {
+		if i%2 == 1 {
+			return 0, fmt.Errorf("%w: %d", errOdd, i)
+		}
+		return i / 2, nil
+	}
+
+	out, errs := CastErr(half, feed(0, 1, 2, 3, 4, 5, 6))
+	var have []error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		have = collect(errs)
+	}()
+	assert.Equal(t, []int{0, 1, 2, 3}, collect(out))
+	<-done
+	assert.Len(t, have, 3)
+	for i, err := range have {
+		assert.ErrorIs(t, err, errOdd)
+		assert.EqualError(t, err, fmt.Sprintf("odd: %d", 2*i+1))
+	}
+
+	out, errs = CastErr(half, feed[int]())
+	assert.Empty(t, collect(out))
+	assert.Empty(t, collect(errs))
+}
+
+func TestGroupReduce(t *testing.T) {
+	type purchase struct {
+		user   string
+		amount int
+	}
+	user := func(p purchase) string { return p.user }
+	total := func(acc int, p purchase) int { return acc + p.amount }
+	zero := func() int { return 0 }
+
+	have := GroupReduce(user, total, zero, feed(
+		purchase{"ann", 3}, purchase{"bob", 5}, purchase{"ann", 4}, purchase{"cat", 1}, purchase{"bob", 2},
+	))
+	assert.Equal(t, map[string]int{"ann": 7, "bob": 7, "cat": 1}, have)
+
+	inits := 0
+	amounts := GroupReduce(user, func(acc []int, p purchase) []int { return append(acc, p.amount) }, func() []int {
+		inits++
+		return []int{}
+	}, feed(purchase{"ann", 3}, purchase{"ann", 4}, purchase{"bob", 5}))
+	assert.Equal(t, map[string][]int{"ann": {3, 4}, "bob": {5}}, amounts)
+	assert.Equal(t, 2, inits, "init should be called once per key")
+
+	assert.Empty(t, GroupReduce(user, total, zero, feed[purchase]()))
+}
+
+// flaky returns a generator which fails "failures" times before yielding
+// the values of vals, followed by the end of the stream
+func flaky(failures int, vals ...int) func() (int, bool, error) {
+	return func() (int, bool, error) {
+		if failures > 0 {
+			failures--
+			return 0, false, fmt.Errorf("%d failures left", failures)
+		}
+		if len(vals) == 0 {
+			return 0, false, nil
+		}
+		v := vals[0]
+		vals = vals[1:]
+		return v, true, nil
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	panicky := func(failures int, vals ...int) func() (int, bool) {
+		f := flaky(failures, vals...)
+		return func() (int, bool) {
+			v, more, err := f()
+			if err != nil {
+				panic(err)
+			}
+			return v, more
+		}
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, collect(Generate(ctx, panicky(3, 1, 2, 3), 3)))
+	assert.Empty(t, collect(Generate(ctx, panicky(3, 1, 2, 3), 2)), "too many consecutive panics should close the output")
+	assert.Equal(t, []int{1, 2}, collect(Generate(ctx, panicky(0, 1, 2), 0)))
+
+	calls := 0
+	alternating := func() (int, bool) {
+		calls++
+		if calls%2 == 1 {
+			panic("odd call")
+		}
+		return calls, calls < 10
+	}
+	assert.Equal(t, []int{2, 4, 6, 8}, collect(Generate(ctx, alternating, 1)), "successes should reset the failure count")
+}
+
+func TestGenerateErr(t *testing.T) {
+	ctx := context.Background()
+
+	out, errs := GenerateErr(ctx, flaky(2, 1, 2, 3), 2)
+	assert.Equal(t, []int{1, 2, 3}, collect(out))
+	assert.NoError(t, <-errs)
+
+	out, errs = GenerateErr(ctx, flaky(3, 1, 2, 3), 2)
+	assert.Empty(t, collect(out))
+	assert.EqualError(t, <-errs, "0 failures left", "the last error should be reported")
+	_, ok := <-errs
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithCancel(ctx)
+	forever := func() (int, bool, error) { return 1, true, nil }
+	out, errs = GenerateErr(ctx, forever, 0)
+	assert.Equal(t, []int{1, 1, 1}, Head(3, out))
+	cancel()
+	Process(RW(out))
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
@@ -1,5 +1,7 @@
 package maps
 
+import "github.com/kendfss/iters/slices"
+
 func Keys2[K comparable, V any](m map[K]V) []K {
 	out := make([]K, len(m))
 	ctr := 0
@@ -122,6 +124,21 @@ func Copy[K comparable, V any](dst, src map[K]V) {
 	}
 }
 
+// Merged returns a new map holding the key/value pairs of every argument.
+// When a key appears in several maps, the value from the last of them is kept.
+// Unlike Copy, none of the arguments are modified
+func Merged[K comparable, V any](ms ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range ms {
+		size += len(m)
+	}
+	out := make(map[K]V, size)
+	for _, m := range ms {
+		Copy(out, m)
+	}
+	return out
+}
+
 // DeleteFunc deletes any key/value pairs from m for which del returns true.
 func DeleteFunc[K comparable, V any](m map[K]V, del func(K, V) bool) {
 	for k, v := range m {
@@ -162,6 +179,25 @@ func Extend[K comparable, V any](m map[K]V, key K, val V) map[K]V {
 	return m
 }
 
+// GetOr returns the value stored under k, or def if there is none
+func GetOr[K comparable, V any](m map[K]V, k K, def V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return def
+}
+
+// GetOrCompute returns the value stored under k. If there is none, it calls f
+// and stores the result under k before returning it, which makes it handy for memoization
+func GetOrCompute[K comparable, V any](m map[K]V, k K, f func() V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	v := f()
+	m[k] = v
+	return v
+}
+
 // Getter returns a castable operator that fetches the element of that index from a slice.
 // see Get and Cast for more info
 func Getter[V any, K comparable](table map[K]V) func(K) V {
@@ -218,3 +254,32 @@ func Mass[K comparable, V any](m map[K][]V) (out int) {
 	}
 	return
 }
+
+// Reduce folds f over the entries of a map, starting from init.
+// Map iteration order is random, so f must not depend on the order in which
+// entries are visited
+func Reduce[K comparable, V, A any](f func(A, K, V) A, init A, m map[K]V) A {
+	for k, v := range m {
+		init = f(init, k, v)
+	}
+	return init
+}
+
+// Entries returns the key/value pairs of a map, in no particular order
+func Entries[K comparable, V any](m map[K]V) []slices.LR[K, V] {
+	out := make([]slices.LR[K, V], 0, len(m))
+	for k, v := range m {
+		out = append(out, slices.LR[K, V]{Left: k, Right: v})
+	}
+	return out
+}
+
+// FromEntries builds a map from key/value pairs, later pairs overwriting earlier
+// ones which share their key
+func FromEntries[K comparable, V any](entries []slices.LR[K, V]) map[K]V {
+	out := make(map[K]V, len(entries))
+	for _, e := range entries {
+		out[e.Left] = e.Right
+	}
+	return out
+}
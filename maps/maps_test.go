@@ -158,6 +158,27 @@ func TestCopy(t *testing.T) {
 	}
 }
 
+func TestMerged(t *testing.T) {
+	a := map[string]int{"a": 1, "b": 2}
+	b := map[string]int{"b": 3, "c": 4}
+	c := map[string]int{"c": 5, "d": 6}
+	got := Merged(a, b, c)
+	want := map[string]int{"a": 1, "b": 3, "c": 5, "d": 6}
+	if !Equal(got, want) {
+		t.Errorf("Merged(%v, %v, %v) = %v, want %v", a, b, c, got, want)
+	}
+	if !Equal(a, map[string]int{"a": 1, "b": 2}) || !Equal(b, map[string]int{"b": 3, "c": 4}) || !Equal(c, map[string]int{"c": 5, "d": 6}) {
+		t.Errorf("Merged modified its arguments: %v, %v, %v", a, b, c)
+	}
+	got["e"] = 7
+	if _, ok := a["e"]; ok {
+		t.Errorf("Merged result aliases its first argument")
+	}
+	if got := Merged[string, int](); got == nil || len(got) != 0 {
+		t.Errorf("Merged() = %#v, want empty map", got)
+	}
+}
+
 func TestDeleteFunc(t *testing.T) {
 	mc := Clone(m1)
 	DeleteFunc(mc, func(int, int) bool { return false })
@@ -170,3 +191,86 @@ func TestDeleteFunc(t *testing.T) {
 		t.Errorf("DeleteFunc result = %v, want %v", mc, want)
 	}
 }
+
+func TestGetOr(t *testing.T) {
+	m := map[string]int{"a": 1, "zero": 0}
+	if got := GetOr(m, "a", 9); got != 1 {
+		t.Errorf("GetOr(%v, %q, 9) = %d, want 1", m, "a", got)
+	}
+	if got := GetOr(m, "zero", 9); got != 0 {
+		t.Errorf("GetOr(%v, %q, 9) = %d, want 0", m, "zero", got)
+	}
+	if got := GetOr(m, "b", 9); got != 9 {
+		t.Errorf("GetOr(%v, %q, 9) = %d, want 9", m, "b", got)
+	}
+	if _, ok := m["b"]; ok {
+		t.Errorf("GetOr stored its default")
+	}
+}
+
+func TestGetOrCompute(t *testing.T) {
+	m := map[string]int{"a": 1}
+	calls := 0
+	f := func() int {
+		calls++
+		return 7
+	}
+	if got := GetOrCompute(m, "a", f); got != 1 || calls != 0 {
+		t.Errorf("GetOrCompute(m, %q, f) = %d after %d calls, want 1 after 0", "a", got, calls)
+	}
+	if got := GetOrCompute(m, "b", f); got != 7 || calls != 1 {
+		t.Errorf("GetOrCompute(m, %q, f) = %d after %d calls, want 7 after 1", "b", got, calls)
+	}
+	if v, ok := m["b"]; !ok || v != 7 {
+		t.Errorf("GetOrCompute did not store the computed value: %v", m)
+	}
+	if got := GetOrCompute(m, "b", f); got != 7 || calls != 1 {
+		t.Errorf("GetOrCompute(m, %q, f) = %d after %d calls, want 7 after 1", "b", got, calls)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, k, v int) int { return acc + v }
+	if got := Reduce(sum, 0, m1); got != 30 {
+		t.Errorf("Reduce(sum, 0, %v) = %d, want 30", m1, got)
+	}
+	if got := Reduce(sum, 7, map[int]int{}); got != 7 {
+		t.Errorf("Reduce(sum, 7, {}) = %d, want 7", got)
+	}
+
+	countOdd := func(acc, k int, v string) int {
+		if k%2 == 1 {
+			acc++
+		}
+		return acc
+	}
+	if got := Reduce(countOdd, 0, m2); got != 1 {
+		t.Errorf("Reduce(countOdd, 0, %v) = %d, want 1", m2, got)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	entries := Entries(m2)
+	if len(entries) != len(m2) {
+		t.Fatalf("len(Entries(%v)) = %d, want %d", m2, len(entries), len(m2))
+	}
+	if got := FromEntries(entries); !Equal(got, m2) {
+		t.Errorf("FromEntries(Entries(%v)) = %v, want %v", m2, got, m2)
+	}
+
+	slices.SortKey(slices.LR[int, string].L, entries)
+	want := []slices.LR[int, string]{
+		{Left: 1, Right: "2"}, {Left: 2, Right: "4"}, {Left: 4, Right: "8"}, {Left: 8, Right: "16"},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("sorted Entries(%v) = %v, want %v", m2, entries, want)
+	}
+
+	if got := Entries(map[int]int{}); len(got) != 0 {
+		t.Errorf("Entries({}) = %v, want []", got)
+	}
+	dup := []slices.LR[int, int]{{Left: 1, Right: 1}, {Left: 2, Right: 2}, {Left: 1, Right: 3}}
+	if got, want := FromEntries(dup), map[int]int{1: 3, 2: 2}; !Equal(got, want) {
+		t.Errorf("FromEntries(%v) = %v, want %v", dup, got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package slices
+
+// EditOp names the operations an Edit can perform
+type EditOp int
+
+const (
+	EditKeep   EditOp = iota // the value is common to both slices
+	EditDelete               // the value is only in the old slice
+	EditInsert               // the value is only in the new slice
+)
+
+// EditOp.String returns the name of the operation
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "Keep"
+	case EditDelete:
+		return "Delete"
+	case EditInsert:
+		return "Insert"
+	}
+	return "EditOp(?)"
+}
+
+// Edit is a single step of an edit script (see Diff)
+type Edit[E any] struct {
+	Op    EditOp
+	Value E
+}
+
+// Diff returns a minimal edit script which transforms a into b.
+// It keeps a longest common subsequence of the two and deletes or inserts
+// everything else, deletions coming before insertions wherever they touch.
+// It is O(len(a)*len(b)) in both time and space
+func Diff[E comparable](a, b []E) []Edit[E] {
+	table := lcsTable(a, b)
+	out := make([]Edit[E], 0, len(a)+len(b)-table[0][0])
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, Edit[E]{EditKeep, a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			out = append(out, Edit[E]{EditDelete, a[i]})
+			i++
+		default:
+			out = append(out, Edit[E]{EditInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, Edit[E]{EditDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, Edit[E]{EditInsert, b[j]})
+	}
+	return out
+}
+
+// ApplyDiff replays an edit script, as produced by Diff, against a and returns
+// the resulting slice. It fails with ErrEdit if the script does not fit a
+func ApplyDiff[E comparable](a []E, script []Edit[E]) ([]E, error) {
+	out := []E{}
+	i := 0
+	for _, edit := range script {
+		switch edit.Op {
+		case EditKeep, EditDelete:
+			if i >= len(a) || a[i] != edit.Value {
+				return nil, ErrEdit
+			}
+			if edit.Op == EditKeep {
+				out = append(out, a[i])
+			}
+			i++
+		case EditInsert:
+			out = append(out, edit.Value)
+		default:
+			return nil, ErrEdit
+		}
+	}
+	if i != len(a) {
+		return nil, ErrEdit
+	}
+	return out, nil
+}
+
+// LCS returns a longest common subsequence of a and b: the longest slice whose
+// elements appear, in order though not necessarily adjacent, in both (see IsSubsequence).
+// It is O(len(a)*len(b)) in both time and space
+func LCS[E comparable](a, b []E) []E {
+	table := lcsTable(a, b)
+	out := make([]E, 0, table[0][0])
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
+
+// lcsTable returns a table whose [i][j]'th entry is the length of a longest
+// common subsequence of a[i:] and b[j:]
+func lcsTable[E comparable](a, b []E) [][]int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else if table[i+1][j] >= table[i][j+1] {
+				table[i][j] = table[i+1][j]
+			} else {
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	return table
+}
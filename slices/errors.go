@@ -5,4 +5,9 @@ import "errors"
 var (
 	ErrInsuff = errors.New("Insufficient Elements")
 	ErrIndex  = errors.New("slice index out of range")
+	ErrLength = errors.New("slice lengths differ")
+	ErrWeight = errors.New("weights must be non-negative and not all zero")
+	ErrEdit   = errors.New("edit script does not fit slice")
+	ErrBounds = errors.New("lower bound exceeds upper bound")
+	ErrType   = errors.New("element has the wrong type")
 )
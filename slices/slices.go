@@ -3,10 +3,12 @@ package slices
 import (
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"os"
+	"reflect"
+	"strings"
 	"sync"
-	"unsafe"
 
 	"github.com/kendfss/but"
 	"github.com/kendfss/oprs"
@@ -49,6 +51,57 @@ func EqualFunc[E1, E2 any](eq func(E1, E2) bool, s1 []E1, s2 []E2) bool {
 	return true
 }
 
+// EqualWithin reports whether two slices have the same length and every pair of
+// corresponding elements differs by no more than tol. Equal infinities match, but,
+// as with Equal, NaNs match nothing (see EqualWithinNaN)
+func EqualWithin[F rules.Float](tol F, a, b []F) bool {
+	return EqualFunc(func(x, y F) bool { return within(tol, x, y) }, a, b)
+}
+
+// EqualWithinNaN works like EqualWithin, but considers NaNs equal to each other
+func EqualWithinNaN[F rules.Float](tol F, a, b []F) bool {
+	return EqualFunc(func(x, y F) bool {
+		if isNaN(x) || isNaN(y) {
+			return isNaN(x) && isNaN(y)
+		}
+		return within(tol, x, y)
+	}, a, b)
+}
+
+func within[F rules.Float](tol, x, y F) bool {
+	return x == y || math.Abs(float64(x-y)) <= float64(tol)
+}
+
+func isNaN[F rules.Float](x F) bool {
+	return x != x
+}
+
+// EqualUnordered reports whether two slices contain the same elements, with the
+// same multiplicities, regardless of their order.
+func EqualUnordered[E comparable](s1, s2 []E) bool {
+	return EqualUnorderedFunc(func(e E) E { return e }, s1, s2)
+}
+
+// EqualUnorderedFunc works like EqualUnordered, but compares elements by the
+// result of applying hash to them.
+func EqualUnorderedFunc[E any, K comparable](hash func(E) K, s1, s2 []E) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	counts := make(map[K]int, len(s1))
+	for _, e := range s1 {
+		counts[hash(e)]++
+	}
+	for _, e := range s2 {
+		k := hash(e)
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	return true
+}
+
 // Compare compares the elements of s1 and s2.
 // The elements are compared sequentially, starting at index 0,
 // until one element is not equal to the other.
@@ -154,12 +207,124 @@ func ContainsAnyFunc[E any](eq func(E, E) bool, s []E, args ...E) bool {
 	return false
 }
 
+// IndexSlice returns the index of the first occurrence of sub, as a contiguous
+// run, in s, or -1 if not present. The empty slice is found at index 0.
+func IndexSlice[E comparable](s, sub []E) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ContainsSlice reports whether sub is present, as a contiguous run, in s.
+func ContainsSlice[E comparable](s, sub []E) bool {
+	return IndexSlice(s, sub) >= 0
+}
+
+// HasPrefix reports whether s begins with prefix.
+func HasPrefix[E comparable](s, prefix []E) bool {
+	return len(s) >= len(prefix) && Equal(s[:len(prefix)], prefix)
+}
+
+// HasPrefixFunc reports whether s begins with prefix, using eq to compare elements.
+func HasPrefixFunc[E1, E2 any](eq func(E1, E2) bool, s []E1, prefix []E2) bool {
+	return len(s) >= len(prefix) && EqualFunc(eq, s[:len(prefix)], prefix)
+}
+
+// HasSuffix reports whether s ends with suffix.
+func HasSuffix[E comparable](s, suffix []E) bool {
+	return len(s) >= len(suffix) && Equal(s[len(s)-len(suffix):], suffix)
+}
+
+// HasSuffixFunc reports whether s ends with suffix, using eq to compare elements.
+func HasSuffixFunc[E1, E2 any](eq func(E1, E2) bool, s []E1, suffix []E2) bool {
+	return len(s) >= len(suffix) && EqualFunc(eq, s[len(s)-len(suffix):], suffix)
+}
+
+// CommonPrefix returns the longest run of elements with which both a and b begin.
+// The result shares its backing array with a.
+func CommonPrefix[E comparable](a, b []E) []E {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// CommonSuffix returns the longest run of elements with which both a and b end.
+// The result shares its backing array with a.
+func CommonSuffix[E comparable](a, b []E) []E {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return a[len(a)-n:]
+}
+
+// TrimPrefix returns s without the leading prefix, or s itself if it does not
+// begin with prefix. The result shares its backing array with s.
+func TrimPrefix[E comparable](s, prefix []E) []E {
+	if HasPrefix(s, prefix) {
+		return s[len(prefix):]
+	}
+	return s
+}
+
+// TrimSuffix returns s without the trailing suffix, or s itself if it does not
+// end with suffix. The result shares its backing array with s.
+func TrimSuffix[E comparable](s, suffix []E) []E {
+	if HasSuffix(s, suffix) {
+		return s[:len(s)-len(suffix)]
+	}
+	return s
+}
+
+// TrimFunc returns the sub-slice of s left after removing all leading and trailing
+// elements which satisfy pred. The result shares its backing array with s.
+func TrimFunc[E any](pred func(E) bool, s []E) []E {
+	i, j := 0, len(s)
+	for i < j && pred(s[i]) {
+		i++
+	}
+	for j > i && pred(s[j-1]) {
+		j--
+	}
+	return s[i:j]
+}
+
+// Trim returns the sub-slice of s left after removing all leading and trailing
+// elements contained in cutset. The result shares its backing array with s.
+func Trim[E comparable](s []E, cutset ...E) []E {
+	return TrimFunc(func(e E) bool { return Contains(cutset, e) }, s)
+}
+
+// IsSubsequence reports whether the elements of sub appear in s in the same order,
+// though not necessarily next to one another.
+// The empty slice is a subsequence of every slice.
+func IsSubsequence[E comparable](sub, s []E) bool {
+	i := 0
+	for _, e := range s {
+		if i == len(sub) {
+			break
+		}
+		if e == sub[i] {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
 // Insert inserts the values v... into s at index i,
 // returning the modified slice.
 // In the returned slice r, r[i] == v[0].
-// Insert panics if i is out of range.
+// Insert panics, with an error wrapping ErrIndex, if i is outside [0, len(s)].
 // This function is O(len(s) + len(v)).
 func Insert[E any](s []E, i int, args ...E) []E {
+	if i < 0 || i > len(s) {
+		panic(fmt.Errorf("%w: cannot insert at %d into slice of length %d", ErrIndex, i, len(s)))
+	}
 	tot := len(s) + len(args)
 	if tot <= cap(s) {
 		s2 := s[:tot]
@@ -174,6 +339,16 @@ func Insert[E any](s []E, i int, args ...E) []E {
 	return s2
 }
 
+// InsertAt works like Insert, but accepts negative indices, which are measured
+// from the end of s as they are by Get, so InsertAt(s, -1, v) inserts v before
+// the last element of s
+func InsertAt[E any](s []E, i int, args ...E) []E {
+	if i < 0 {
+		i += len(s)
+	}
+	return Insert(s, i, args...)
+}
+
 // Delete removes the elements s[i:j] from s, returning the modified slice.
 // Delete panics if s[i:j] is not a valid slice of s.
 // Delete modifies the contents of the slice s; it does not create a new slice.
@@ -183,6 +358,19 @@ func Delete[E any](s []E, i, j int) []E {
 	return append(s[:i], s[j:]...)
 }
 
+// DeleteValue removes every occurrence of v from s, returning the modified slice.
+// DeleteValue modifies the contents of the slice s; it does not create a new slice.
+func DeleteValue[E comparable](s []E, v E) []E {
+	i := 0
+	for _, e := range s {
+		if e != v {
+			s[i] = e
+			i++
+		}
+	}
+	return s[:i]
+}
+
 // Clone returns a copy of the slice.
 // The elements are copied using assignment, so this is a shallow clone.
 func Clone[E any](s []E) []E {
@@ -196,6 +384,16 @@ func Clone[E any](s []E) []E {
 	// return append([]E{}, s...)
 }
 
+// CloneFunc returns a copy of the slice whose elements are copied using clone,
+// so that, for example, CloneFunc(Clone[int], matrix) is a deep clone of a [][]int.
+func CloneFunc[E any](clone func(E) E, s []E) []E {
+	// Preserve nil in case it matters.
+	if s == nil {
+		return nil
+	}
+	return Cast(clone, s)
+}
+
 // Compact replaces consecutive runs of equal elements with a single copy.
 // This is like the uniq command found on Unix.
 // Compact modifies the contents of the slice s; it does not create a new slice.
@@ -238,6 +436,61 @@ func CompactFunc[E any](eq func(E, E) bool, s []E) []E {
 	return s[:i]
 }
 
+// CompactRuns is like Compact but also returns, for each surviving element,
+// the length of the run of equal elements it replaced.
+// CompactRuns modifies the contents of the slice s; it does not create a new slice.
+func CompactRuns[E comparable](s []E) ([]E, []int) {
+	if len(s) == 0 {
+		return s, nil
+	}
+	runs := []int{1}
+	i := 1
+	last := s[0]
+	for _, v := range s[1:] {
+		if v != last {
+			s[i] = v
+			i++
+			last = v
+			runs = append(runs, 1)
+		} else {
+			runs[len(runs)-1]++
+		}
+	}
+	return s[:i], runs
+}
+
+// RunLength encodes s as the value and length of each of its runs of equal
+// elements, in order. Unlike CompactRuns, it leaves s untouched
+func RunLength[E comparable](s []E) []LR[E, int] {
+	out := []LR[E, int]{}
+	for _, e := range s {
+		if n := len(out); n > 0 && out[n-1].Left == e {
+			out[n-1].Right++
+		} else {
+			out = append(out, LR[E, int]{Left: e, Right: 1})
+		}
+	}
+	return out
+}
+
+// RunLengthDecode expands the runs produced by RunLength back into the slice
+// they encode. Runs with non-positive lengths contribute nothing
+func RunLengthDecode[E any](runs []LR[E, int]) []E {
+	n := 0
+	for _, run := range runs {
+		if run.Right > 0 {
+			n += run.Right
+		}
+	}
+	out := make([]E, 0, n)
+	for _, run := range runs {
+		for i := 0; i < run.Right; i++ {
+			out = append(out, run.Left)
+		}
+	}
+	return out
+}
+
 // Grow increases the slice's capacity, if necessary, to guarantee space for
 // another n elements. After Grow(n), at least n elements can be appended
 // to the slice without another allocation. Grow may modify elements of the
@@ -297,6 +550,15 @@ func Cast[E, V any](f func(E) V, s []E) []V {
 	return out
 }
 
+// CastIndexed works like Cast, but also passes f the index of each element
+func CastIndexed[E, V any](f func(int, E) V, s []E) []V {
+	out := make([]V, len(s))
+	for i, e := range s {
+		out[i] = f(i, e)
+	}
+	return out
+}
+
 // Filter returns a slice featuring all truthy elements
 func Filter(args []bool) (out []bool) {
 	for _, e := range args {
@@ -315,6 +577,16 @@ func FilterFunc[E any](f func(E) bool, args []E) (out []E) {
 	return out
 }
 
+// FilterIndexed works like FilterFunc, but also passes the position of each element to the predicate
+func FilterIndexed[E any](pred func(int, E) bool, s []E) (out []E) {
+	for i, e := range s {
+		if pred(i, e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
 // Get returns the i'th element from a slice, even if i is negative
 // uses the same indexing convention as python lists/tuples
 func Get[E any, I rules.Integer](index I, slice []E) E {
@@ -372,6 +644,62 @@ func Zip[K any](args ...[]K) (out [][]K) {
 	return
 }
 
+// ZipReduce calls f with one element from each argument, per index, up to the length of the shortest
+// ZipReduce(add, {1, 2}, {3, 4}, {5, 6, 7}) == {9, 12}
+func ZipReduce[E, O any](f func(...E) O, args ...[]E) []O {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make([]O, len(args[Shortest(args...)]))
+	for i := range out {
+		row := make([]E, len(args))
+		for j, arg := range args {
+			row[j] = arg[i]
+		}
+		out[i] = f(row...)
+	}
+	return out
+}
+
+// ZipLongest convolves type-equivalent slices up to the length of the longest,
+// padding the shorter ones with fill
+// ZipLongest(0, {1, 2}, {3}) == {{1, 3}, {2, 0}}
+func ZipLongest[K any](fill K, args ...[]K) [][]K {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make([][]K, len(args[Longest(args...)]))
+	for i := range out {
+		out[i] = make([]K, len(args))
+		for j, arg := range args {
+			if i < len(arg) {
+				out[i][j] = arg[i]
+			} else {
+				out[i][j] = fill
+			}
+		}
+	}
+	return out
+}
+
+// Unzip inverts Zip, transposing a matrix so that its i'th row becomes the
+// i'th column of the result. Like Zip, it truncates ragged rows to the length
+// of the shortest, so Unzip(Zip(args...)) == args iff args all have the same length
+func Unzip[K any](rows [][]K) [][]K {
+	if len(rows) == 0 {
+		return nil
+	}
+	return Zip(rows...)
+}
+
+// Transpose returns a matrix whose i'th column is the i'th row of m.
+// Unlike Unzip, ragged matrices are not truncated: rows shorter than the
+// longest are padded with zero values, so the result always has as many
+// rows as the longest row of m
+func Transpose[T any](m [][]T) [][]T {
+	return ZipLongest(*new(T), m...)
+}
+
 type (
 	LR[L, R any] struct {
 		// LR holds two values, Left and Right, of any types.
@@ -382,8 +710,18 @@ type (
 		// Pair holds two values, Left and Right, of any type.
 		Left, Right T
 	}
+	LMR[L, M, R any] struct {
+		// LMR holds three values, Left, Middle and Right, of any types.
+		Left   L
+		Middle M
+		Right  R
+	}
 )
 
+func (lmr LMR[L, M, R]) Split() (l L, m M, r R) {
+	return lmr.Left, lmr.Middle, lmr.Right
+}
+
 func (p Pair[T]) Split() (l, r T) {
 	return p.Left, p.Right
 }
@@ -441,6 +779,15 @@ func Zip2[L, R any](left []L, right []R) (out []LR[L, R]) {
 	return out
 }
 
+// Unzip2 inverts Zip2, splitting pairs into their left and right halves
+func Unzip2[L, R any](pairs []LR[L, R]) ([]L, []R) {
+	left, right := make([]L, len(pairs)), make([]R, len(pairs))
+	for i, pair := range pairs {
+		left[i], right[i] = pair.Left, pair.Right
+	}
+	return left, right
+}
+
 // Convolve pairs of type-distinct slices with a closure
 func Zip3[L, R any](left []L, right []R) (out []func() (L, R)) {
 	if len(left) > len(right) {
@@ -457,9 +804,19 @@ func Zip3[L, R any](left []L, right []R) (out []func() (L, R)) {
 }
 
 // Concatenate slices
+// the result is allocated once, and is nil if there is nothing to concatenate
 func Chain[E any](args ...[]E) (out []E) {
+	n := 0
+	for _, arg := range args {
+		n += len(arg)
+	}
+	if n == 0 {
+		return nil
+	}
+	out = make([]E, n)
+	i := 0
 	for _, arg := range args {
-		out = append(out, arg...)
+		i += copy(out[i:], arg)
 	}
 	return out
 }
@@ -550,13 +907,20 @@ func Reversed[E any](slice []E) []E {
 // Reverse a slice in place
 // func Reverse[[]E ~[]E, E any](slice []E) {
 func Reverse[E any](slice []E) {
-	if len(slice) > 1 {
-		for i, j := 0, len(slice)-1; j > 0; i, j = i+1, j-1 {
-			slice[i], slice[j] = slice[j], slice[i]
-		}
+	for i, j := 0, len(slice)-1; i < j; i, j = i+1, j-1 {
+		slice[i], slice[j] = slice[j], slice[i]
 	}
 }
 
+// ReverseRange reverses s[i:j] in place, leaving the rest of s untouched.
+// It panics, with an error wrapping ErrIndex, unless 0 <= i <= j <= len(s)
+func ReverseRange[E any](s []E, i, j int) {
+	if i < 0 || j < i || j > len(s) {
+		panic(fmt.Errorf("%w: cannot reverse [%d:%d] of slice of length %d", ErrIndex, i, j, len(s)))
+	}
+	Reverse(s[i:j])
+}
+
 // Swap the elements at a pair of indices (in place)
 func Swap[E any](slice []E, i, j int) []E {
 	slice[i], slice[j] = slice[j], slice[i]
@@ -575,33 +939,32 @@ func Len[I rules.Integer, E any](slice []E) I {
 	return I(len(slice))
 }
 
-// Check if all elements of a slice are true
+// All reports whether every element of a slice is true.
+// It stops at the first false element, and is true for an empty slice
 func All(args []bool) bool {
-	// return AllFunc(oprs.IsTrue, args...)
-	switch len(args) {
-	case 0:
-		return false
-	case 1:
-		return args[0]
-	default:
-		return args[0] && All(args[1:])
-	}
+	return AllFunc(oprs.IsTrue, args)
 }
 
-// use a custom predicate to check if all elements of a slice
-// have a common property
+// AllFunc reports whether every element of a slice satisfies pred.
+// It stops at the first element which does not, and is true for an empty slice
 func AllFunc[E any](pred func(E) bool, slice []E) bool {
-	return All(Cast(pred, slice))
+	for _, e := range slice {
+		if !pred(e) {
+			return false
+		}
+	}
+	return true
 }
 
-// Check if any elements of a slice are true
-func Any(args ...bool) bool {
-	return AnyFunc(oprs.IsTrue, args...)
+// Any reports whether any element of a slice is true.
+// It stops at the first true element, and is false for an empty slice
+func Any(args []bool) bool {
+	return AnyFunc(oprs.IsTrue, args)
 }
 
-// use a custom predicate to check if any elements of a slice
-// have a common property
-func AnyFunc[E any](pred func(E) bool, slice ...E) bool {
+// AnyFunc reports whether any element of a slice satisfies pred.
+// It stops at the first element which does, and is false for an empty slice
+func AnyFunc[E any](pred func(E) bool, slice []E) bool {
 	for _, e := range slice {
 		if pred(e) {
 			return true
@@ -643,16 +1006,66 @@ func Extremal[E any](operator func(E, E) bool, args ...E) (out int) {
 	return out
 }
 
+// Clamp returns v bounded to the closed interval [lo, hi].
+// It panics, with an error wrapping ErrBounds, if lo > hi
+func Clamp[N rules.Ordered](lo, hi, v N) N {
+	if lo > hi {
+		panic(fmt.Errorf("%w: cannot clamp to [%v, %v]", ErrBounds, lo, hi))
+	}
+	return clamp(lo, hi, v)
+}
+
+// ClampSlice returns a new slice holding the elements of s bounded to the closed
+// interval [lo, hi]. It panics, with an error wrapping ErrBounds, if lo > hi
+func ClampSlice[N rules.Ordered](lo, hi N, s []N) []N {
+	if lo > hi {
+		panic(fmt.Errorf("%w: cannot clamp to [%v, %v]", ErrBounds, lo, hi))
+	}
+	out := make([]N, len(s))
+	for i, v := range s {
+		out[i] = clamp(lo, hi, v)
+	}
+	return out
+}
+
+func clamp[N rules.Ordered](lo, hi, v N) N {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
 // Deprecated, use Chain
 func Union[E any](first []E, rest ...[]E) []E {
 	fmt.Fprintln(os.Stderr, "Union is deprecated, use Chain")
 	return Chain(first, Chain(rest...))
 }
 
-// Deprecated, use Chain
-func Flatter[M ~[][]E, E any](arg M) (out []E) {
-	fmt.Fprintln(os.Stderr, "Flatter is deprecated, use Chain")
-	return Chain(arg...)
+// Flatten2 concatenates the members of a slice of slices, in order.
+// It is equivalent to Chain(s...)
+func Flatten2[E any](s [][]E) []E {
+	return Chain(s...)
+}
+
+// Flatten3 concatenates the members of the members of a slice of slices of slices, in order.
+// Go cannot express arbitrarily nested slices generically, so each depth needs its own function
+func Flatten3[E any](s [][][]E) []E {
+	n := 0
+	for _, e := range s {
+		for _, f := range e {
+			n += len(f)
+		}
+	}
+	out := make([]E, 0, n)
+	for _, e := range s {
+		for _, f := range e {
+			out = append(out, f...)
+		}
+	}
+	return out
 }
 
 // Snap breaks a slice into sections of given width
@@ -674,6 +1087,34 @@ func Snap[I rules.I, E any](width I, arg []E) (out [][]E) {
 	return out
 }
 
+// SnapString breaks a string into substrings of the given width, counted in
+// runes rather than bytes; the last may be shorter.
+// SnapString(2, "héllo") == []string{"hé", "ll", "o"}
+// The result is nil unless width is positive
+func SnapString(width int, s string) (out []string) {
+	if width <= 0 {
+		return nil
+	}
+	bounds := runeBounds(s)
+	for i := 0; i < len(bounds)-1; i += width {
+		j := i + width
+		if j > len(bounds)-1 {
+			j = len(bounds) - 1
+		}
+		out = append(out, s[bounds[i]:bounds[j]])
+	}
+	return out
+}
+
+// runeBounds returns the byte offsets at which each rune of s starts, followed by len(s)
+func runeBounds(s string) []int {
+	out := make([]int, 0, len(s)+1)
+	for i := range s {
+		out = append(out, i)
+	}
+	return append(out, len(s))
+}
+
 // Split "cuts" the slice at all occurrences of breaker
 func Split[E comparable](slice []E, breaker E) [][]E {
 	return SplitFunc(oprs.Eq[E], slice, breaker)
@@ -724,6 +1165,55 @@ func SplitAfterPred[E any](function func(E) bool, slice []E) [][]E {
 	return out
 }
 
+// ChunkBy "cuts" the slice wherever the key changes, grouping consecutive elements
+// which share a key into runs, like python's itertools.groupby. Elements with equal
+// keys that are not adjacent end up in different runs, so sort s first to group them all.
+// The runs share s's underlying array but have their capacity capped, so appending to
+// one does not overwrite its neighbour
+func ChunkBy[E any, K comparable](key func(E) K, s []E) [][]E {
+	out := [][]E{}
+	start := 0
+	var prev K
+	for i, e := range s {
+		k := key(e)
+		if i > 0 && k != prev {
+			out = append(out, s[start:i:i])
+			start = i
+		}
+		prev = k
+	}
+	if start < len(s) {
+		out = append(out, s[start:len(s):len(s)])
+	}
+	return out
+}
+
+// SplitAt returns s[:i] and s[i:]
+// negative indices are counted from the end, as with Get,
+// and out-of-range indices are clamped to the bounds of s instead of panicking
+func SplitAt[E any](i int, s []E) ([]E, []E) {
+	if i < 0 {
+		i += len(s)
+	}
+	if i < 0 {
+		i = 0
+	} else if i > len(s) {
+		i = len(s)
+	}
+	return s[:i], s[i:]
+}
+
+// Span splits s after its longest leading run of elements which satisfy pred,
+// returning that run and the remainder, in a single pass. As with SplitAt, both
+// share s's underlying array
+func Span[E any](pred func(E) bool, s []E) (prefix, rest []E) {
+	i := 0
+	for i < len(s) && pred(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
 // Deprecated, use Repeat
 func Ones[T rules.Integer](count T) []T {
 	fmt.Fprintln(os.Stderr, "Ones is deprecated, use Repeat")
@@ -739,12 +1229,32 @@ func Anify[E any](slice []E) []any {
 	return out
 }
 
-// Repeat returns a slice, with length count, of integers initialized to seed
-// if you want to repeat an empty slice you should use Tee instead
+// Unify converts a slice of empty interfaces into one of type T; it is the
+// inverse of Anify. It fails, with an error wrapping ErrType, at the first
+// element which is not a T. nil elements are only accepted if T is an interface
+func Unify[T any](slice []any) ([]T, error) {
+	out := make([]T, len(slice))
+	nilable := any(*new(T)) == nil
+	for i, e := range slice {
+		if e == nil && nilable {
+			continue
+		}
+		v, ok := e.(T)
+		if !ok {
+			return nil, fmt.Errorf("%w: element %d is %T, not %v", ErrType, i, e, reflect.TypeOf((*T)(nil)).Elem())
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// Repeat returns a slice, with length count, of copies of seed
+// the copies are shallow, so if T is a reference type (a slice, map, pointer, etc)
+// every element shares the same underlying value; use Tee for independent slices
 func Repeat[T any, C rules.Integer](seed T, count C) []T {
 	out := make([]T, count)
 	for i := range out {
-		out[i] = *(*T)(unsafe.Pointer(&seed))
+		out[i] = seed
 	}
 	return out
 }
@@ -850,6 +1360,34 @@ func PrefillSeed[T any](slice []T, seed T, by uint) []T {
 	return append(out, slice...)
 }
 
+// PadRight returns a copy of s extended with copies of fill up to the given length.
+// Slices that are already at least that long are copied whole, not truncated
+func PadRight[E any](fill E, length int, s []E) []E {
+	if length < len(s) {
+		length = len(s)
+	}
+	out := make([]E, length)
+	n := copy(out, s)
+	for i := n; i < length; i++ {
+		out[i] = fill
+	}
+	return out
+}
+
+// PadLeft works like PadRight, but puts the copies of fill before the elements of s
+func PadLeft[E any](fill E, length int, s []E) []E {
+	if length < len(s) {
+		length = len(s)
+	}
+	out := make([]E, length)
+	pad := length - len(s)
+	for i := 0; i < pad; i++ {
+		out[i] = fill
+	}
+	copy(out[pad:], s)
+	return out
+}
+
 func Cartesian[L, R any](left []L, right []R) []LR[L, R] {
 	out := make([]LR[L, R], len(left)*len(right))
 	ctr := 0
@@ -863,6 +1401,43 @@ func Cartesian[L, R any](left []L, right []R) []LR[L, R] {
 	return out
 }
 
+// Cartesian3 returns the cartesian product of three slices of any types, in
+// lexicographic order, ie. the last argument varies fastest.
+// Type parameters cannot vary in number, so heterogeneous products have fixed
+// arities (see Cartesian); use CartesianN for any number of slices of one type
+func Cartesian3[L, M, R any](left []L, middle []M, right []R) []LMR[L, M, R] {
+	out := make([]LMR[L, M, R], 0, len(left)*len(middle)*len(right))
+	for _, l := range left {
+		for _, m := range middle {
+			for _, r := range right {
+				out = append(out, LMR[L, M, R]{Left: l, Middle: m, Right: r})
+			}
+		}
+	}
+	return out
+}
+
+// CartesianN returns the cartesian product of any number of type-equivalent slices
+// the tuples are in lexicographic order, ie. the last argument varies fastest
+// CartesianN() == [][]E{{}}, and the product is empty if any argument is empty
+func CartesianN[E any](args ...[]E) [][]E {
+	n := 1
+	for _, arg := range args {
+		n *= len(arg)
+	}
+	out := make([][]E, n)
+	for i := range out {
+		tuple := make([]E, len(args))
+		rem := i
+		for j := len(args) - 1; j >= 0; j-- {
+			tuple[j] = args[j][rem%len(args[j])]
+			rem /= len(args[j])
+		}
+		out[i] = tuple
+	}
+	return out
+}
+
 // Count returns the number of occurences of item in rack
 func Count[T comparable](item T, rack []T) (out uint) {
 	for _, e := range rack {
@@ -883,6 +1458,69 @@ func CountFunc[E any](eq func(E, E) bool, item E, rack []E) (out uint) {
 	return
 }
 
+// CountPred returns the number of elements of rack which satisfy pred
+func CountPred[E any](pred func(E) bool, rack []E) (out uint) {
+	for _, e := range rack {
+		if pred(e) {
+			out++
+		}
+	}
+	return
+}
+
+// Frequencies returns a map from each distinct element of s to its number of occurences
+func Frequencies[E comparable](s []E) map[E]int {
+	return FrequenciesFunc(func(e E) E { return e }, s)
+}
+
+// FrequenciesFunc returns a map from each distinct key of the elements of s
+// to the number of elements which have it
+func FrequenciesFunc[E any, K comparable](key func(E) K, s []E) map[K]int {
+	out := make(map[K]int)
+	for _, e := range s {
+		out[key(e)]++
+	}
+	return out
+}
+
+// TopFrequencies returns the n most frequent elements of s paired with their
+// counts, in decreasing order of count. Ties are ordered by first appearance
+func TopFrequencies[E comparable](n int, s []E) []LR[E, int] {
+	out := []LR[E, int]{}
+	index := make(map[E]int)
+	for _, e := range s {
+		if i, ok := index[e]; ok {
+			out[i].Right++
+		} else {
+			index[e] = len(out)
+			out = append(out, LR[E, int]{Left: e, Right: 1})
+		}
+	}
+	SortStableFunc(func(a, b LR[E, int]) bool { return a.Right > b.Right }, out)
+	if n < 0 {
+		n = 0
+	}
+	if n < len(out) {
+		out = out[:n]
+	}
+	return out
+}
+
+// Mode returns the most frequent element of s and its number of occurences.
+// Ties are won by the first to appear. ok is false iff s is empty
+func Mode[E comparable](s []E) (mode E, count int, ok bool) {
+	if top := TopFrequencies(1, s); len(top) > 0 {
+		return top[0].Left, top[0].Right, true
+	}
+	return
+}
+
+// ModeN returns the k most frequent elements of s, in decreasing order of frequency.
+// see TopFrequencies for more info
+func ModeN[E comparable](k int, s []E) []E {
+	return Cast(LR[E, int].L, TopFrequencies(k, s))
+}
+
 // Indices returns the positions at which item can be found in rack
 func Indices[T comparable](item T, rack []T) (out []int) {
 	for i, e := range rack {
@@ -903,6 +1541,16 @@ func IndicesFunc[T comparable](eq func(T, T) bool, item T, rack []T) (out []int)
 	return
 }
 
+// IndexAllPred returns the positions of every element of s which satisfies pred
+func IndexAllPred[E any](pred func(E) bool, s []E) (out []int) {
+	for i, e := range s {
+		if pred(e) {
+			out = append(out, i)
+		}
+	}
+	return
+}
+
 // Dot returns a dot product analog of left with right.
 // Dot({2, 3}, {1, 2}) === {2, 6}
 // Dot({2}, {1, 2}) === {2, 0}
@@ -954,8 +1602,9 @@ func Mul[N rules.Num](left, right []N) []N {
 	return out
 }
 
-// Rotated returns the index-shifted of a given slice
+// Rotated returns an index-shifted copy of a given slice
 // as though the operation were taking place on a torus (no elements lost or added)
+// the argument is left untouched
 func Rotated[T any, I rules.I](slice []T, steps I) []T {
 	if len(slice) == 0 {
 		return make([]T, 0)
@@ -964,7 +1613,25 @@ func Rotated[T any, I rules.I](slice []T, steps I) []T {
 	if steps < 0 {
 		steps += I(len(slice))
 	}
-	return append(slice[steps:], slice[:steps]...)
+	out := make([]T, len(slice))
+	n := copy(out, slice[steps:])
+	copy(out[n:], slice[:steps])
+	return out
+}
+
+// Rotate index-shifts a slice in place, in the same manner as Rotated
+// it is O(len(slice)) and does not allocate
+func Rotate[T any, I rules.I](slice []T, steps I) {
+	if len(slice) == 0 {
+		return
+	}
+	steps %= I(len(slice))
+	if steps < 0 {
+		steps += I(len(slice))
+	}
+	Reverse(slice[:steps])
+	Reverse(slice[steps:])
+	Reverse(slice)
 }
 
 // Send is like Cast but for impure functions
@@ -984,13 +1651,10 @@ func Pointers[T any](s []T) []*T {
 	return out
 }
 
-// Values returns a slice of values to members of a slice of pointers
+// Deprecated, use Deref
 func Values[T any](s []*T) []T {
-	out := make([]T, len(s))
-	for i, e := range s {
-		out[i] = *e
-	}
-	return out
+	fmt.Fprintln(os.Stderr, "Values is deprecated, use Deref")
+	return Deref(s)
 }
 
 // Partition uses a function to categorize elements of a slice
@@ -1003,6 +1667,57 @@ func Partition[K comparable, V any](pred func(V) K, slice []V) map[K][]V {
 	return out
 }
 
+// Split2 separates the elements of s which satisfy pred from those which do not,
+// preserving their order
+func Split2[E any](pred func(E) bool, s []E) (yes, no []E) {
+	for _, e := range s {
+		if pred(e) {
+			yes = append(yes, e)
+		} else {
+			no = append(no, e)
+		}
+	}
+	return yes, no
+}
+
+// Associate builds a map from the key/value pairs f returns for each element of s.
+// When two elements share a key, the later one wins
+func Associate[E any, K comparable, V any](f func(E) (K, V), s []E) map[K]V {
+	out := make(map[K]V, len(s))
+	for _, e := range s {
+		k, v := f(e)
+		out[k] = v
+	}
+	return out
+}
+
+// AssociateBy indexes the elements of s by key.
+// When two elements share a key, the later one wins
+func AssociateBy[E any, K comparable](key func(E) K, s []E) map[K]E {
+	out := make(map[K]E, len(s))
+	for _, e := range s {
+		out[key(e)] = e
+	}
+	return out
+}
+
+// DedupeReduce collapses elements which share a key into one, combining them,
+// from left to right, with merge. Keys appear in the order they were first seen
+func DedupeReduce[E any, K comparable](key func(E) K, merge func(a, b E) E, s []E) []E {
+	out := []E{}
+	index := make(map[K]int)
+	for _, e := range s {
+		k := key(e)
+		if i, ok := index[k]; ok {
+			out[i] = merge(out[i], e)
+		} else {
+			index[k] = len(out)
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
 // Deprecated, use Repeat
 func Copies[T any, U rules.I](length U, val T) []T {
 	fmt.Fprintln(os.Stderr, "Copies is deprecated, use Repeat")
@@ -1081,6 +1796,7 @@ func Walks[T any, I rules.Integer](length I, slice []T) (out [][]T) {
 func Enumerate[I rules.Integer, T any](slice []T) []func() (I, T) {
 	out := make([]func() (I, T), len(slice))
 	for i, e := range slice {
+		i, e := i, e
 		out[i] = func() (I, T) {
 			return I(i), e
 		}
@@ -1088,6 +1804,16 @@ func Enumerate[I rules.Integer, T any](slice []T) []func() (I, T) {
 	return out
 }
 
+// Enumerated returns a slice of index/value pairs, the Left of each being the
+// index of its Right in the given slice
+func Enumerated[I rules.Integer, T any](slice []T) []LR[I, T] {
+	out := make([]LR[I, T], len(slice))
+	for i, e := range slice {
+		out[i] = LR[I, T]{Left: I(i), Right: e}
+	}
+	return out
+}
+
 // Tee returns a slice of independent slices
 func Tee[T any, I rules.Integer](seed []T, count I) [][]T {
 	out := make([][]T, count)
@@ -1097,6 +1823,30 @@ func Tee[T any, I rules.Integer](seed []T, count I) [][]T {
 	return out
 }
 
+// TeeChan returns count independent, unbuffered channels which each yield the
+// elements of s, in order, before closing. Unlike Tee, s is never copied; the
+// channels read from it as their consumers do, so s must not be modified until
+// they have all been drained.
+// Each channel is fed by its own goroutine, which blocks until the channel is
+// fully drained, so every channel must be drained or its goroutine leaks
+func TeeChan[T any](count int, s []T) []<-chan T {
+	if count < 0 {
+		count = 0
+	}
+	out := make([]<-chan T, count)
+	for i := range out {
+		ch := make(chan T)
+		go func() {
+			defer close(ch)
+			for _, e := range s {
+				ch <- e
+			}
+		}()
+		out[i] = ch
+	}
+	return out
+}
+
 // Make initializes a slice
 func Make[T any, I rules.Integer](length I) []T {
 	return make([]T, length)
@@ -1334,7 +2084,55 @@ func Repeater[T any, I rules.Int](count I) func(T) []T {
 
 // Choose selects an element of the gicen slice at random
 func Choose[T any](arg []T) T {
-	return arg[rand.Intn(len(arg))]
+	return choose(rand.Intn, arg)
+}
+
+// ChooseRand works like Choose, but draws from the given source
+// so that results can be reproduced
+func ChooseRand[T any](r *rand.Rand, arg []T) T {
+	return choose(r.Intn, arg)
+}
+
+func choose[T any](intn func(int) int, arg []T) T {
+	return arg[intn(len(arg))]
+}
+
+// ChooseWeighted selects an element of the given slice at random, with probability
+// proportional to the weight at its index.
+// It fails with ErrLength if the slices' lengths differ, and with ErrWeight if any
+// weight is negative, NaN or infinite, if none is positive, or if they sum to infinity
+func ChooseWeighted[T any](weights []float64, s []T) (T, error) {
+	return chooseWeighted(rand.Float64(), weights, s)
+}
+
+// chooseWeighted does the work of ChooseWeighted with the uniform variate, u, in [0, 1)
+func chooseWeighted[T any](u float64, weights []float64, s []T) (T, error) {
+	if len(weights) != len(s) {
+		return *new(T), ErrLength
+	}
+	cum := make([]float64, len(weights))
+	total := 0.
+	last := -1 // the last index with a positive weight
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return *new(T), ErrWeight
+		}
+		if w > 0 {
+			last = i
+		}
+		total += w
+		cum[i] = total
+	}
+	if total <= 0 || math.IsInf(total, 0) {
+		return *new(T), ErrWeight
+	}
+	r := u * total
+	// u*total may round up to total, in which case no cum[i] exceeds it
+	i := search(len(cum), func(i int) bool { return cum[i] > r })
+	if i > last {
+		i = last
+	}
+	return s[i], nil
 }
 
 // Get an element of a slice situated at a point (x,y) when
@@ -1343,6 +2141,30 @@ func Getxy[E any](slice []E, stride, x, y int) E {
 	return slice[y*stride+x]
 }
 
+// GetxySafe works like Getxy, but reports whether (x,y) lies within the matrix
+// instead of reading the wrong cell, or panicking, when it does not
+func GetxySafe[E any](slice []E, stride, x, y int) (E, bool) {
+	if !inxy(len(slice), stride, x, y) {
+		return *new(E), false
+	}
+	return slice[y*stride+x], true
+}
+
+// Setxy sets the element of a slice situated at a point (x,y) when the slice
+// is interpreted as a matrix whose rows are stride elements long.
+// It panics, with an error wrapping ErrIndex, if (x,y) lies outside the matrix
+func Setxy[E any](slice []E, stride, x, y int, v E) {
+	if !inxy(len(slice), stride, x, y) {
+		panic(fmt.Errorf("%w: (%d, %d) is outside a matrix of %d elements with stride %d", ErrIndex, x, y, len(slice), stride))
+	}
+	slice[y*stride+x] = v
+}
+
+// inxy reports whether (x,y) lies within a matrix of the given length and stride
+func inxy(length, stride, x, y int) bool {
+	return 0 <= x && x < stride && 0 <= y && y*stride+x < length
+}
+
 // ReduceAs applies Reduce after converting a slice of real numbers
 // an overflow-safe way for operating on small numbers
 func ReduceAs[I, O rules.Real](op func(O, O) O, args ...I) O {
@@ -1353,6 +2175,59 @@ func ReduceAs[I, O rules.Real](op func(O, O) O, args ...I) O {
 	return Reduce(op, rack)
 }
 
+// Sum adds up the elements of a slice
+// the sum of an empty slice is 0
+func Sum[N rules.Num](s []N) (out N) {
+	for _, e := range s {
+		out += e
+	}
+	return out
+}
+
+// Prod multiplies the elements of a slice
+// the product of an empty slice is 1
+func Prod[N rules.Num](s []N) N {
+	out := N(1)
+	for _, e := range s {
+		out *= e
+	}
+	return out
+}
+
+// CumSum returns the running totals of a slice: the i'th element of the result
+// is the sum of s[:i+1]
+func CumSum[N rules.Num](s []N) []N {
+	return scan(func(acc, e N) N { return acc + e }, s)
+}
+
+// CumProd returns the running products of a slice: the i'th element of the result
+// is the product of s[:i+1]
+func CumProd[N rules.Num](s []N) []N {
+	return scan(func(acc, e N) N { return acc * e }, s)
+}
+
+// scan returns the intermediate results of reducing s with f
+func scan[E any](f func(E, E) E, s []E) []E {
+	out := make([]E, len(s))
+	for i, e := range s {
+		if i == 0 {
+			out[i] = e
+		} else {
+			out[i] = f(out[i-1], e)
+		}
+	}
+	return out
+}
+
+// SumAs adds up the elements of a slice after converting them to another type of real number
+// an overflow-safe way for summing small numbers, see ReduceAs for more info
+func SumAs[I, O rules.Real](s []I) (out O) {
+	for _, e := range s {
+		out += O(e)
+	}
+	return out
+}
+
 func Windows[T any](src []T, size int) (out [][]T) {
 	if size > 0 {
 		for i := 0; i+size <= len(src); i++ {
@@ -1362,13 +2237,99 @@ func Windows[T any](src []T, size int) (out [][]T) {
 	return out
 }
 
+// WindowsStep returns the windows of the given size whose starting points are
+// step elements apart, so step == 1 is equivalent to Windows and step == size
+// cuts s into consecutive chunks. Only whole windows are emitted, so trailing
+// elements which cannot fill one are dropped.
+// The result is nil unless both size and step are positive
+func WindowsStep[T any](size, step int, s []T) (out [][]T) {
+	if size > 0 && step > 0 {
+		for i := 0; i+size <= len(s); i += step {
+			out = append(out, s[i:i+size])
+		}
+	}
+	return out
+}
+
+// WindowsString returns the substrings of s which are size runes long, each
+// starting one rune after its predecessor.
+// WindowsString(2, "héllo") == []string{"hé", "él", "ll", "lo"}
+func WindowsString(size int, s string) (out []string) {
+	if size > 0 {
+		bounds := runeBounds(s)
+		for i := 0; i+size < len(bounds); i++ {
+			out = append(out, s[bounds[i]:bounds[i+size]])
+		}
+	}
+	return out
+}
+
+// WindowReduce folds each sliding window of the given size, starting from init every time
+// WindowReduce(2, add, 0, []int{1, 2, 3}) == []int{3, 5}
+// it is O(len(s)*size); prefer MovingSum or MovingMean for plain aggregates of integers
+func WindowReduce[E, A any](size int, f func(A, E) A, init A, s []E) (out []A) {
+	for _, window := range Windows(s, size) {
+		acc := init
+		for _, e := range window {
+			acc = f(acc, e)
+		}
+		out = append(out, acc)
+	}
+	return out
+}
+
+// MovingSum returns the sum of each sliding window of the given size.
+// For integers it is O(len(s)) because each sum is derived, exactly, from its
+// predecessor. That update can lose all precision in floating point arithmetic,
+// for instance when a large value enters and then leaves the window, so for
+// floating point (and complex) numbers each window is summed afresh, which is O(len(s)*size)
+func MovingSum[N rules.Num](size int, s []N) []N {
+	if size <= 0 || size > len(s) {
+		return nil
+	}
+	out := make([]N, len(s)-size+1)
+	if N(1)/N(2) != 0 { // not an integer type
+		for i := range out {
+			for _, e := range s[i : i+size] {
+				out[i] += e
+			}
+		}
+		return out
+	}
+	for _, e := range s[:size] {
+		out[0] += e
+	}
+	for i := 1; i < len(out); i++ {
+		out[i] = out[i-1] - s[i-1] + s[i+size-1]
+	}
+	return out
+}
+
+// MovingMean returns the arithmetic mean of each sliding window of the given size
+// like MovingSum, it is O(len(s)) for integers and O(len(s)*size) for floating point numbers
+func MovingMean[N rules.Real](size int, s []N) []float64 {
+	sums := MovingSum(size, s)
+	out := make([]float64, len(sums))
+	for i, sum := range sums {
+		out[i] = float64(sum) / float64(size)
+	}
+	return out
+}
+
+// Resize returns a slice whose length is the product of shape, suitable for
+// holding a matrix of that shape. It does not reshape anything: a slice that
+// is too long is truncated, and one that is too short is extended with zero
+// values. If shape is empty, s is returned unchanged
 func Resize[T any](s []T, shape ...int) []T {
+	if len(shape) == 0 {
+		return s
+	}
 	dim := Reduce(real.Mul[int], shape)
 	switch l := len(s); cmp(dim, l) {
 	case 1:
-		return s[:dim]
+		return append(s, make([]T, dim-l)...)
 	case -1:
-		return append(s, make([]T, l-dim)...)
+		return s[:dim]
 	default:
 		return s
 	}
@@ -1412,6 +2373,9 @@ func Fshowln[T any](w io.Writer, args []T) {
 	}
 }
 
+// Join interleaves the elements of s with sep and adds them up
+// it is meant for non-string monoids; strings are concatenated in quadratic time,
+// use JoinString for those instead
 func Join[T rules.Ordered](s []T, sep T) (out T) {
 	for i, e := range s {
 		out += e
@@ -1422,6 +2386,8 @@ func Join[T rules.Ordered](s []T, sep T) (out T) {
 	return out
 }
 
+// JoinFunc interleaves the elements of s with sep and folds them with add
+// see Join for more info
 func JoinFunc[T any](add func(T, T) T, s []T, sep T) (out T) {
 	for i, e := range s {
 		out = add(out, e)
@@ -1432,6 +2398,41 @@ func JoinFunc[T any](add func(T, T) T, s []T, sep T) (out T) {
 	return out
 }
 
+// JoinString concatenates the elements of s, placing sep between them,
+// in linear time
+func JoinString(sep string, s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	n := len(sep) * (len(s) - 1)
+	for _, e := range s {
+		n += len(e)
+	}
+	var b strings.Builder
+	b.Grow(n)
+	b.WriteString(s[0])
+	for _, e := range s[1:] {
+		b.WriteString(sep)
+		b.WriteString(e)
+	}
+	return b.String()
+}
+
+// Intersperse returns a new slice with sep placed between each pair of adjacent
+// elements of s, but not before the first or after the last
+// Intersperse(0, []int{1, 2, 3}) == []int{1, 0, 2, 0, 3}
+func Intersperse[E any](sep E, s []E) []E {
+	if len(s) == 0 {
+		return []E{}
+	}
+	out := make([]E, 0, 2*len(s)-1)
+	out = append(out, s[0])
+	for _, e := range s[1:] {
+		out = append(out, sep, e)
+	}
+	return out
+}
+
 // Pairwise(ABCD) -> AB BC CD
 func Pairwise[T any](args ...T) [][]T {
 	tee := Tee(args, 2)
@@ -1439,6 +2440,17 @@ func Pairwise[T any](args ...T) [][]T {
 	return Zip(a, b)
 }
 
+// PairwiseWrap is like Pairwise but also pairs the last element with the first,
+// as though the slice were a ring (eg. the vertices of a polygon)
+// PairwiseWrap(ABC) -> AB BC CA
+func PairwiseWrap[T any](s []T) []LR[T, T] {
+	out := make([]LR[T, T], len(s))
+	for i, e := range s {
+		out[i] = LR[T, T]{Left: e, Right: s[(i+1)%len(s)]}
+	}
+	return out
+}
+
 func SortedKey[T any, U rules.Ordered](k func(T) U, s []T) []T {
 	key := Key[T, U](k)
 	return SortedFunc(key.Lt, s)
@@ -1473,6 +2485,29 @@ func CastAsync[I, O any](cast func(I) O, args ...I) []O {
 	return out
 }
 
+// CastAsyncErr behaves like CastAsync for fallible functions.
+// The results are in the same order as args, with zero values in place of any
+// that failed, and the error returned is that of the earliest argument to fail
+func CastAsyncErr[I, O any](cast func(I) (O, error), args []I) ([]O, error) {
+	wg := new(sync.WaitGroup)
+	wg.Add(len(args))
+	out := make([]O, len(args))
+	errs := make([]error, len(args))
+	for i, arg := range args {
+		go func(i int, arg I) {
+			defer wg.Done()
+			out[i], errs[i] = cast(arg)
+		}(i, arg)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
 // Rcast returns a slice whose values are the result of the
 // application of the given function to all elements of the given slice
 // it behaves like "map" in languages whose hashtables are called "associative array" or "dictionary"
@@ -1484,18 +2519,79 @@ func Rcast[I any, O any](fs []func([]I) O, s []I) []O {
 	return out
 }
 
+// Pipe threads value through each of fns in turn, returning the last result.
+// Pipe(x, f, g) == g(f(x)), and no functions yields value itself
+func Pipe[T any](value T, fns ...func(T) T) T {
+	for _, f := range fns {
+		value = f(value)
+	}
+	return value
+}
+
+// Compose returns a function which threads its argument through each of fns
+// in turn, as Pipe does. Compose(f, g)(x) == g(f(x))
+func Compose[T any](fns ...func(T) T) func(T) T {
+	return func(value T) T {
+		return Pipe(value, fns...)
+	}
+}
+
 // Shuffle returns a permutation
 func Shuffle[T any](args []T) []T {
-	indices := rand.Perm(len(args))
+	return shuffle(rand.Perm, args)
+}
+
+// ShuffleRand works like Shuffle, but draws from the given source
+// so that results can be reproduced
+func ShuffleRand[T any](r *rand.Rand, args []T) []T {
+	return shuffle(r.Perm, args)
+}
+
+// ShuffleInPlace permutes the given slice with a Fisher-Yates shuffle
+func ShuffleInPlace[T any](args []T) {
+	shuffleInPlace(rand.Intn, args)
+}
+
+// ShuffleInPlaceRand works like ShuffleInPlace, but draws from the given source
+// so that results can be reproduced
+func ShuffleInPlaceRand[T any](r *rand.Rand, args []T) {
+	shuffleInPlace(r.Intn, args)
+}
+
+func shuffleInPlace[T any](intn func(int) int, args []T) {
+	for i := len(args) - 1; i > 0; i-- {
+		j := intn(i + 1)
+		args[i], args[j] = args[j], args[i]
+	}
+}
+
+func shuffle[T any](perm func(int) []int, args []T) []T {
 	out := make([]T, len(args))
-	// ctr := 0
-	for j, i := range indices {
+	for j, i := range perm(len(args)) {
 		out[j] = args[i]
-		// ctr++
 	}
 	return out
 }
 
+// Sample returns k distinct elements of s, chosen uniformly at random, in random order.
+// k is clamped to [0, len(s)] and s is not modified
+func Sample[T any](k int, s []T) []T {
+	if k < 0 {
+		k = 0
+	}
+	if k > len(s) {
+		k = len(s)
+	}
+	pool := Clone(s)
+	for i := 0; i < k; i++ {
+		j := i + rand.Intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return Clip(pool[:k])
+}
+
+// Deref returns the values pointed to by the members of a slice of pointers.
+// It panics if any of them is nil, see DerefOr for a safe alternative
 func Deref[T any](arg []*T) []T {
 	out := make([]T, len(arg))
 	for i, e := range arg {
@@ -1504,6 +2600,19 @@ func Deref[T any](arg []*T) []T {
 	return out
 }
 
+// DerefOr works like Deref, but substitutes def for nil pointers instead of panicking
+func DerefOr[T any](def T, arg []*T) []T {
+	out := make([]T, len(arg))
+	for i, e := range arg {
+		if e == nil {
+			out[i] = def
+		} else {
+			out[i] = *e
+		}
+	}
+	return out
+}
+
 func Ref[T any](arg []T) []*T {
 	out := make([]*T, len(arg))
 	for i, e := range arg {
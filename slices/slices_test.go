@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,6 +79,40 @@ func TestEqual(t *testing.T) {
 	}
 }
 
+func TestEqualUnordered(t *testing.T) {
+	tests := []struct {
+		s1, s2 []int
+		want   bool
+	}{
+		{nil, nil, true},
+		{[]int{}, nil, true},
+		{[]int{1, 2, 3}, []int{3, 1, 2}, true},
+		{[]int{1, 1, 2}, []int{1, 2, 1}, true},
+		{[]int{1, 1, 2}, []int{1, 2, 2}, false},
+		{[]int{1, 2}, []int{1, 2, 2}, false},
+		{[]int{1, 2, 3}, []int{1, 2, 4}, false},
+	}
+	for _, test := range tests {
+		if got := EqualUnordered(test.s1, test.s2); got != test.want {
+			t.Errorf("EqualUnordered(%v, %v) = %t, want %t", test.s1, test.s2, got, test.want)
+		}
+	}
+
+	s := oracle.RandNums[int](30)
+	if !EqualUnordered(s, Shuffle(s)) {
+		t.Errorf("EqualUnordered(%v, Shuffle(%v)) = false, want true", s, s)
+	}
+
+	s3 := []string{"a", "B", "b"}
+	s4 := []string{"b", "A", "b"}
+	if !EqualUnorderedFunc(strings.ToLower, s3, s4) {
+		t.Errorf("EqualUnorderedFunc(strings.ToLower, %v, %v) = false, want true", s3, s4)
+	}
+	if EqualUnorderedFunc(strings.ToLower, s3, []string{"a", "A", "b"}) {
+		t.Errorf("EqualUnorderedFunc(strings.ToLower, %v, [a A b]) = true, want false", s3)
+	}
+}
+
 func TestEqualFunc(t *testing.T) {
 	for _, test := range equalIntTests {
 		// if got := EqualFunc(test.s1, test.s2, equal[int]); got != test.want {
@@ -118,6 +154,36 @@ func TestEqualFunc(t *testing.T) {
 	}
 }
 
+func TestEqualWithin(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	type test struct {
+		tol           float64
+		a, b          []float64
+		want, wantNaN bool
+	}
+	tests := []test{
+		{0.1, []float64{1, 2, 3}, []float64{1.05, 1.95, 3}, true, true},
+		{0.01, []float64{1, 2, 3}, []float64{1.05, 1.95, 3}, false, false},
+		{0, []float64{1, 2}, []float64{1, 2}, true, true},
+		{1, []float64{1, 2}, []float64{1, 2, 3}, false, false},
+		{1, []float64{}, nil, true, true},
+		{0.1, []float64{inf, -inf}, []float64{inf, -inf}, true, true},
+		{0.1, []float64{inf}, []float64{-inf}, false, false},
+		{0.1, []float64{1, nan}, []float64{1, nan}, false, true},
+		{0.1, []float64{1, nan}, []float64{1, 1}, false, false},
+		{inf, []float64{nan}, []float64{1}, false, false},
+	}
+	for _, test := range tests {
+		if have := EqualWithin(test.tol, test.a, test.b); have != test.want {
+			t.Errorf("EqualWithin(%v, %v, %v) = %t, want %t", test.tol, test.a, test.b, have, test.want)
+		}
+		if have := EqualWithinNaN(test.tol, test.a, test.b); have != test.wantNaN {
+			t.Errorf("EqualWithinNaN(%v, %v, %v) = %t, want %t", test.tol, test.a, test.b, have, test.wantNaN)
+		}
+	}
+	assert.True(t, EqualWithin[float32](0.5, []float32{1, 2}, []float32{1.25, 1.75}))
+}
+
 var compareIntTests = []struct {
 	s1, s2 []int
 	want   int
@@ -323,6 +389,16 @@ func TestIndexFunc(t *testing.T) {
 	}
 }
 
+func TestIndexAllPred(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	data := []int{1, 2, 3, 4, 6, 7}
+	assert.Equal(t, []int{1, 3, 4}, IndexAllPred(even, data))
+	assert.Empty(t, IndexAllPred(func(i int) bool { return i > 10 }, data))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, IndexAllPred(func(int) bool { return true }, data))
+	assert.Empty(t, IndexAllPred(even, []int{}))
+	assert.Equal(t, Indices(4, data), IndexAllPred(func(i int) bool { return i == 4 }, data))
+}
+
 func TestContains(t *testing.T) {
 	for _, test := range indexTests {
 		if got := Contains(test.s, test.v); got != (test.want != -1) {
@@ -331,6 +407,127 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestIndexSlice(t *testing.T) {
+	tests := []struct {
+		s, sub []int
+		want   int
+	}{
+		{[]int{1, 2, 3}, []int{2, 3}, 1},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, 0},
+		{[]int{1, 2, 3}, []int{1, 3}, -1},
+		{[]int{1, 1, 1, 2}, []int{1, 1, 2}, 1},
+		{[]int{1, 2, 1, 2, 3}, []int{1, 2, 3}, 2},
+		{[]int{1, 2}, []int{1, 2, 3}, -1},
+		{[]int{1, 2, 3}, nil, 0},
+		{nil, nil, 0},
+		{nil, []int{1}, -1},
+	}
+	for _, test := range tests {
+		if got := IndexSlice(test.s, test.sub); got != test.want {
+			t.Errorf("IndexSlice(%v, %v) = %d, want %d", test.s, test.sub, got, test.want)
+		}
+		if got := ContainsSlice(test.s, test.sub); got != (test.want != -1) {
+			t.Errorf("ContainsSlice(%v, %v) = %t, want %t", test.s, test.sub, got, test.want != -1)
+		}
+	}
+}
+
+func TestHasPrefixSuffix(t *testing.T) {
+	tests := []struct {
+		s, affix       []int
+		prefix, suffix bool
+	}{
+		{[]int{1, 2, 3}, []int{1, 2}, true, false},
+		{[]int{1, 2, 3}, []int{2, 3}, false, true},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, true, true},
+		{[]int{1, 2, 3}, []int{3, 2, 1}, false, false},
+		{[]int{1, 2}, []int{1, 2, 3}, false, false},
+		{[]int{1, 2}, []int{0, 1, 2}, false, false},
+		{[]int{1, 2, 3}, nil, true, true},
+		{nil, nil, true, true},
+		{nil, []int{1}, false, false},
+	}
+	sameParity := func(a int, b int) bool { return a%2 == b%2 }
+	for _, test := range tests {
+		if got := HasPrefix(test.s, test.affix); got != test.prefix {
+			t.Errorf("HasPrefix(%v, %v) = %t, want %t", test.s, test.affix, got, test.prefix)
+		}
+		if got := HasSuffix(test.s, test.affix); got != test.suffix {
+			t.Errorf("HasSuffix(%v, %v) = %t, want %t", test.s, test.affix, got, test.suffix)
+		}
+		if got := HasPrefixFunc(equal[int], test.s, test.affix); got != test.prefix {
+			t.Errorf("HasPrefixFunc(equal[int], %v, %v) = %t, want %t", test.s, test.affix, got, test.prefix)
+		}
+		if got := HasSuffixFunc(equal[int], test.s, test.affix); got != test.suffix {
+			t.Errorf("HasSuffixFunc(equal[int], %v, %v) = %t, want %t", test.s, test.affix, got, test.suffix)
+		}
+	}
+
+	if !HasPrefixFunc(sameParity, []int{1, 2, 3}, []int{5, 0}) {
+		t.Errorf("HasPrefixFunc(sameParity, [1 2 3], [5 0]) = false, want true")
+	}
+	if !HasSuffixFunc(sameParity, []int{1, 2, 3}, []int{4, 7}) {
+		t.Errorf("HasSuffixFunc(sameParity, [1 2 3], [4 7]) = false, want true")
+	}
+	tokens := []string{"go", "test", "./..."}
+	if !HasPrefixFunc(func(a string, b rune) bool { return rune(a[0]) == b }, tokens, []rune("gt")) {
+		t.Errorf("HasPrefixFunc(firstLetter, %q, \"gt\") = false, want true", tokens)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	s := []int{1, 2, 3, 2, 1}
+	assert.Equal(t, []int{3, 2, 1}, TrimPrefix(s, []int{1, 2}))
+	assert.Equal(t, []int{1, 2, 3}, TrimSuffix(s, []int{2, 1}))
+	assert.Equal(t, []int{3}, Trim(s, 1, 2))
+	assert.Equal(t, []int{3}, TrimFunc(func(i int) bool { return i < 3 }, s))
+
+	t.Run("no match", func(t *testing.T) {
+		for _, have := range [][]int{
+			TrimPrefix(s, []int{2}),
+			TrimSuffix(s, []int{2}),
+			TrimPrefix(s, []int{1, 2, 3, 2, 1, 0}),
+			TrimPrefix(s, nil),
+			Trim(s, 3),
+			Trim(s),
+			TrimFunc(func(int) bool { return false }, s),
+		} {
+			assert.Equal(t, s, have)
+			assert.Equal(t, &s[0], &have[0], "result should alias s")
+		}
+	})
+
+	t.Run("full match", func(t *testing.T) {
+		assert.Empty(t, TrimPrefix(s, s))
+		assert.Empty(t, TrimSuffix(s, s))
+		assert.Empty(t, Trim(s, 1, 2, 3))
+		assert.Empty(t, TrimFunc(func(int) bool { return true }, s))
+		assert.Empty(t, Trim([]int{}, 1))
+	})
+}
+
+func TestIsSubsequence(t *testing.T) {
+	tests := []struct {
+		sub, s []int
+		want   bool
+	}{
+		{[]int{1, 3}, []int{1, 2, 3}, true},
+		{[]int{3, 1}, []int{1, 2, 3}, false},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, true},
+		{[]int{1, 1}, []int{1, 2, 3}, false},
+		{[]int{1, 1}, []int{1, 2, 1}, true},
+		{[]int{1, 2, 3, 4}, []int{1, 2, 3}, false},
+		{nil, []int{1, 2, 3}, true},
+		{nil, nil, true},
+		{[]int{1}, nil, false},
+	}
+	for _, test := range tests {
+		if got := IsSubsequence(test.sub, test.s); got != test.want {
+			t.Errorf("IsSubsequence(%v, %v) = %t, want %t", test.sub, test.s, got, test.want)
+		}
+	}
+}
+
 var insertTests = []struct {
 	s    []int
 	add  []int
@@ -376,6 +573,29 @@ func TestInsert(t *testing.T) {
 	}
 }
 
+func TestInsertBounds(t *testing.T) {
+	s := []int{1, 2, 3}
+	assert.Equal(t, []int{0, 1, 2, 3}, Insert(Clone(s), 0, 0))
+	assert.Equal(t, []int{1, 2, 3, 4}, Insert(Clone(s), len(s), 4))
+
+	for _, i := range []int{-1, len(s) + 1} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "Insert(%v, %d) should panic with an error", s, i)
+				assert.ErrorIs(t, err, ErrIndex)
+				assert.Contains(t, err.Error(), fmt.Sprintf("cannot insert at %d into slice of length 3", i))
+			}()
+			Insert(Clone(s), i, 0)
+		}()
+	}
+
+	assert.Equal(t, []int{1, 2, 0, 3}, InsertAt(Clone(s), -1, 0))
+	assert.Equal(t, []int{0, 1, 2, 3}, InsertAt(Clone(s), -3, 0))
+	assert.Equal(t, []int{1, 0, 2, 3}, InsertAt(Clone(s), 1, 0))
+	assert.Panics(t, func() { InsertAt(Clone(s), -4, 0) })
+}
+
 var deleteTests = []struct {
 	s    []int
 	want []int
@@ -423,6 +643,24 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestDeleteValue(t *testing.T) {
+	tests := []struct {
+		s    []int
+		v    int
+		want []int
+	}{
+		{[]int{1, 2, 2, 3, 2}, 2, []int{1, 3}},
+		{[]int{1, 2, 3}, 4, []int{1, 2, 3}},
+		{[]int{2, 2}, 2, []int{}},
+		{nil, 2, nil},
+	}
+	for _, test := range tests {
+		if got := DeleteValue(Clone(test.s), test.v); !Equal(got, test.want) {
+			t.Errorf("DeleteValue(%v, %d) = %v, want %v", test.s, test.v, got, test.want)
+		}
+	}
+}
+
 func TestClone(t *testing.T) {
 	s1 := []int{1, 2, 3}
 	s2 := Clone(s1)
@@ -442,6 +680,29 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneFunc(t *testing.T) {
+	matrix := [][]int{{1, 2}, {3, 4}}
+	deep := CloneFunc(Clone[int], matrix)
+	assert.Equal(t, matrix, deep)
+	deep[0][0] = 9
+	deep[1] = append(deep[1], 5)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, matrix, "original changed with its deep clone")
+
+	shallow := Clone(matrix)
+	shallow[0][0] = 9
+	assert.Equal(t, 9, matrix[0][0], "shallow clones should share their elements")
+
+	type node struct{ val int }
+	ptrs := []*node{{1}, {2}}
+	copied := CloneFunc(func(n *node) *node { c := *n; return &c }, ptrs)
+	copied[0].val = 7
+	assert.Equal(t, 1, ptrs[0].val)
+
+	if got := CloneFunc(Clone[int], [][]int(nil)); got != nil {
+		t.Errorf("CloneFunc(Clone[int], nil) = %#v, want nil", got)
+	}
+}
+
 var compactTests = []struct {
 	s    []int
 	want []int
@@ -497,6 +758,49 @@ func TestCompactFunc(t *testing.T) {
 	}
 }
 
+func TestCompactRuns(t *testing.T) {
+	for _, test := range compactTests {
+		got, runs := CompactRuns(Clone(test.s))
+		if !Equal(got, test.want) {
+			t.Errorf("CompactRuns(%v) = %v, want %v", test.s, got, test.want)
+		}
+		if len(runs) != len(got) {
+			t.Errorf("CompactRuns(%v) has %d runs for %d elements", test.s, len(runs), len(got))
+		}
+		if sum := Reduce(real.Add[int], runs); sum != len(test.s) {
+			t.Errorf("CompactRuns(%v) runs %v sum to %d, want %d", test.s, runs, sum, len(test.s))
+		}
+	}
+
+	s := []int{1, 1, 1, 2, 3, 3}
+	got, runs := CompactRuns(s)
+	if want, wantRuns := []int{1, 2, 3}, []int{3, 1, 2}; !Equal(got, want) || !Equal(runs, wantRuns) {
+		t.Errorf("CompactRuns(%v) = %v, %v, want %v, %v", s, got, runs, want, wantRuns)
+	}
+}
+
+func TestRunLength(t *testing.T) {
+	type run = LR[int, int]
+	s := []int{1, 1, 1, 2, 3, 3}
+	runs := RunLength(s)
+	assert.Equal(t, []run{{1, 3}, {2, 1}, {3, 2}}, runs)
+	assert.Equal(t, s, RunLengthDecode(runs))
+	assert.Equal(t, []int{1, 1, 1, 2, 3, 3}, s, "RunLength should not modify its argument")
+
+	for _, test := range compactTests {
+		runs := RunLength(test.s)
+		values, counts := CompactRuns(Clone(test.s))
+		assert.Len(t, runs, len(test.want), "RunLength(%v)", test.s)
+		assert.True(t, Equal(values, Cast(run.L, runs)), "RunLength(%v) values", test.s)
+		assert.True(t, Equal(counts, Cast(run.R, runs)), "RunLength(%v) counts", test.s)
+		assert.True(t, Equal(test.s, RunLengthDecode(runs)), "RunLengthDecode(RunLength(%v))", test.s)
+	}
+
+	assert.Equal(t, []run{}, RunLength([]int{}))
+	assert.Equal(t, []int{}, RunLengthDecode([]run{}))
+	assert.Equal(t, []int{2, 2}, RunLengthDecode([]run{{1, 0}, {2, 2}, {3, -1}}))
+}
+
 func TestGrow(t *testing.T) {
 	s1 := []int{1, 2, 3}
 	copy := Clone(s1)
@@ -591,6 +895,21 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestFilterIndexed(t *testing.T) {
+	data := []string{"a", "b", "c", "d", "e", "f", "g"}
+	everyThird := func(i int, _ string) bool { return i%3 == 0 }
+	assert.Equal(t, []string{"a", "d", "g"}, FilterIndexed(everyThird, data))
+
+	dropLastTwo := func(i int, _ string) bool { return i < len(data)-2 }
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, FilterIndexed(dropLastTwo, data))
+
+	both := func(i int, e string) bool { return i%2 == 1 && e != "d" }
+	assert.Equal(t, []string{"b", "f"}, FilterIndexed(both, data))
+
+	assert.Empty(t, FilterIndexed(everyThird, []string{}))
+	assert.Equal(t, FilterFunc(oprs.IsEven[int], []int{3, 4, 8, 1, 0}), FilterIndexed(func(_, e int) bool { return e%2 == 0 }, []int{3, 4, 8, 1, 0}))
+}
+
 func TestZip(t *testing.T) {
 	for i := range Upton[int](nTests) {
 		left := oracle.Mkr(nItems, nMax)
@@ -607,7 +926,137 @@ func TestZip(t *testing.T) {
 	}
 }
 
-func TestFlatter(t *testing.T) {
+func TestZipReduce(t *testing.T) {
+	sum := func(args ...int) int { return Reduce(real.Add[int], args) }
+	have := ZipReduce(sum, []int{1, 2, 3}, []int{10, 20, 30}, []int{100, 200, 300, 400})
+	assert.Equal(t, []int{111, 222, 333}, have)
+	assert.Equal(t, []int(nil), ZipReduce(sum))
+	assert.Equal(t, []int{}, ZipReduce(sum, []int{1, 2}, []int{}))
+}
+
+func TestZipLongest(t *testing.T) {
+	have := ZipLongest(-1, []int{1, 2, 3}, []int{}, []int{10, 20, 30, 40}, []int{100})
+	assert.Equal(t, [][]int{
+		{1, -1, 10, 100},
+		{2, -1, 20, -1},
+		{3, -1, 30, -1},
+		{-1, -1, 40, -1},
+	}, have)
+	assert.Equal(t, Zip([]int{1, 2}, []int{3, 4}), ZipLongest(0, []int{1, 2}, []int{3, 4}))
+	assert.Equal(t, [][]int{}, ZipLongest(0, []int{}, []int{}))
+	assert.Nil(t, ZipLongest[int](0))
+
+	for i := 0; i < nTests; i++ {
+		args := [][]int{oracle.RandNums[int](rand.Intn(10)), oracle.RandNums[int](rand.Intn(10))}
+		assert.Len(t, ZipLongest(0, args...), len(args[Longest(args...)]))
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	matrix := [][]int{{1, 2, 3}, {4, 5, 6}}
+	assert.Equal(t, [][]int{{1, 4}, {2, 5}, {3, 6}}, Unzip(matrix))
+	assert.Equal(t, matrix, Unzip(Unzip(matrix)))
+	assert.Equal(t, [][]int{{1, 4}, {2, 5}}, Unzip([][]int{{1, 2, 3}, {4, 5}}), "ragged rows are truncated")
+	assert.Nil(t, Unzip([][]int{}))
+
+	for i := 0; i < nTests; i++ {
+		left, right := oracle.Mkr(nItems, nMax), Cast(strconv.Itoa, oracle.Mkr(nItems, nMax))
+		l, r := Unzip2(Zip2(left, right))
+		assert.Equal(t, left, l)
+		assert.Equal(t, right, r)
+	}
+	l, r := Unzip2([]LR[int, string]{})
+	assert.Empty(t, l)
+	assert.Empty(t, r)
+}
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name    string
+		m, want [][]int
+	}{
+		{"square", [][]int{{1, 2}, {3, 4}}, [][]int{{1, 3}, {2, 4}}},
+		{"wide", [][]int{{1, 2, 3}, {4, 5, 6}}, [][]int{{1, 4}, {2, 5}, {3, 6}}},
+		{"tall", [][]int{{1, 2}, {3, 4}, {5, 6}}, [][]int{{1, 3, 5}, {2, 4, 6}}},
+		{"single row", [][]int{{1, 2, 3}}, [][]int{{1}, {2}, {3}}},
+		{"single column", [][]int{{1}, {2}, {3}}, [][]int{{1, 2, 3}}},
+		{"ragged", [][]int{{1, 2, 3}, {4}, {5, 6}}, [][]int{{1, 4, 5}, {2, 0, 6}, {3, 0, 0}}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, Transpose(test.m), test.name)
+	}
+	assert.Empty(t, Transpose([][]int{}))
+	assert.Empty(t, Transpose([][]int{{}, {}}))
+
+	m := Snap(3, Upton[int](12))
+	assert.Equal(t, m, Transpose(Transpose(m)))
+}
+
+func TestPad(t *testing.T) {
+	type test struct {
+		length      int
+		right, left []int
+	}
+	s := []int{1, 2, 3}
+	tests := []test{
+		{5, []int{1, 2, 3, 0, 0}, []int{0, 0, 1, 2, 3}},
+		{4, []int{1, 2, 3, 0}, []int{0, 1, 2, 3}},
+		{3, []int{1, 2, 3}, []int{1, 2, 3}},
+		{2, []int{1, 2, 3}, []int{1, 2, 3}},
+		{-1, []int{1, 2, 3}, []int{1, 2, 3}},
+	}
+	for _, test := range tests {
+		right := PadRight(0, test.length, s)
+		assert.Equal(t, test.right, right, "PadRight(0, %d, %v)", test.length, s)
+		left := PadLeft(0, test.length, s)
+		assert.Equal(t, test.left, left, "PadLeft(0, %d, %v)", test.length, s)
+		right[0], left[len(left)-1] = 9, 9
+		assert.Equal(t, []int{1, 2, 3}, s, "padding should copy the slice")
+	}
+	assert.Equal(t, []string{"-", "-"}, PadLeft("-", 2, nil))
+	assert.Equal(t, []string{}, PadRight("-", 0, []string{}))
+}
+
+func TestCartesianN(t *testing.T) {
+	assert.Equal(t, [][]int{{}}, CartesianN[int]())
+	assert.Equal(t, [][]int{{1, 3}, {1, 4}, {2, 3}, {2, 4}}, CartesianN([]int{1, 2}, []int{3, 4}))
+	assert.Equal(t,
+		[][]int{{1, 3, 5}, {1, 3, 6}, {1, 4, 5}, {1, 4, 6}, {2, 3, 5}, {2, 3, 6}, {2, 4, 5}, {2, 4, 6}},
+		CartesianN([]int{1, 2}, []int{3, 4}, []int{5, 6}),
+	)
+	assert.Equal(t, [][]int{}, CartesianN([]int{1, 2}, []int{}, []int{5, 6}))
+
+	left, right := oracle.Mkr(nItems, nMax), oracle.Mkr(nItems+1, nMax)
+	pairs := Cartesian(left, right)
+	tuples := CartesianN(left, right)
+	assert.Equal(t, len(pairs), len(tuples))
+	for i, pair := range pairs {
+		assert.Equal(t, []int{pair.Left, pair.Right}, tuples[i], "#%d", i)
+	}
+}
+
+func TestCartesian3(t *testing.T) {
+	type lmr = LMR[int, string, bool]
+	ints, strs, bools := []int{1, 2}, []string{"a", "b", "c"}, []bool{true, false}
+	have := Cartesian3(ints, strs, bools)
+	require.Len(t, have, len(ints)*len(strs)*len(bools))
+	assert.Equal(t, lmr{1, "a", true}, have[0])
+	assert.Equal(t, lmr{1, "a", false}, have[1])
+	assert.Equal(t, lmr{1, "b", true}, have[2])
+	assert.Equal(t, lmr{2, "a", true}, have[6])
+	assert.Equal(t, lmr{2, "c", false}, have[11])
+
+	tuples := CartesianN([]int{1, 2}, []int{3, 4, 5}, []int{6, 7})
+	for i, e := range Cartesian3([]int{1, 2}, []int{3, 4, 5}, []int{6, 7}) {
+		l, m, r := e.Split()
+		assert.Equal(t, tuples[i], []int{l, m, r}, "#%d: should agree with CartesianN", i)
+	}
+
+	assert.Empty(t, Cartesian3(ints, []string{}, bools))
+	assert.Empty(t, Cartesian3[int, string, bool](nil, nil, nil))
+}
+
+func TestFlatten2(t *testing.T) {
 	const (
 		nItems = 4
 		nMax   = 1000
@@ -617,7 +1066,7 @@ func TestFlatter(t *testing.T) {
 			oracle.Mkr(nItems, nMax),
 			oracle.Mkr(nItems, nMax),
 		}
-		result := Flatter(data)
+		result := Flatten2(data)
 
 		ptr := -1
 		for j, have := range result {
@@ -632,6 +1081,18 @@ func TestFlatter(t *testing.T) {
 	}
 }
 
+func TestFlatten3(t *testing.T) {
+	data := [][][]int{
+		{{0, 1}, {2}},
+		{},
+		{{}, {3, 4, 5}},
+		{{6}},
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, Flatten3(data))
+	assert.Empty(t, Flatten3([][][]int{}))
+	assert.Empty(t, Flatten3([][][]int{{}, {{}}}))
+}
+
 func TestChain(t *testing.T) {
 	const (
 		nMax = 100
@@ -657,6 +1118,38 @@ func TestChain(t *testing.T) {
 	}
 }
 
+func TestChainMany(t *testing.T) {
+	args := Cast(func(i int) []int { return Upto[int](i*10, i*10+i, 1) }, Upton[int](20))
+	want := []int{}
+	for _, arg := range args {
+		want = append(want, arg...)
+	}
+	have := Chain(args...)
+	assert.Equal(t, want, have)
+	assert.Equal(t, len(have), cap(have))
+	assert.Nil(t, Chain[int]())
+	assert.Nil(t, Chain([]int{}, nil))
+}
+
+func BenchmarkChain(b *testing.B) {
+	args := Tee(makeRandomInts(1000), 100)
+	b.Run("append", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var out []int
+			for _, arg := range args {
+				out = append(out, arg...)
+			}
+		}
+	})
+	b.Run("Chain", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Chain(args...)
+		}
+	})
+}
+
 // func TestChained(t *testing.T) {
 // 	const (
 // 		nMax = 100
@@ -698,6 +1191,42 @@ func TestReversed(t *testing.T) {
 				oracle.Quitf(t, "#%d.%d: have %d, want %d", i, j, have, want)
 			}
 		}
+		for j, have := range first {
+			if want := data[len(data)-1-j]; have != want {
+				oracle.Quitf(t, "#%d.%d: have %d, want %d", i, j, have, want)
+			}
+		}
+	}
+}
+
+func TestReverseRange(t *testing.T) {
+	type test struct {
+		i, j int
+		want []int
+	}
+	tests := []test{
+		{1, 4, []int{0, 3, 2, 1, 4}},
+		{0, 5, []int{4, 3, 2, 1, 0}},
+		{2, 2, []int{0, 1, 2, 3, 4}},
+		{0, 0, []int{0, 1, 2, 3, 4}},
+		{3, 5, []int{0, 1, 2, 4, 3}},
+	}
+	for _, test := range tests {
+		have := []int{0, 1, 2, 3, 4}
+		ReverseRange(have, test.i, test.j)
+		assert.Equal(t, test.want, have, "ReverseRange(s, %d, %d)", test.i, test.j)
+	}
+	ReverseRange([]int{}, 0, 0)
+
+	for _, bounds := range [][2]int{{-1, 2}, {3, 2}, {0, 6}} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "ReverseRange(s, %d, %d) should panic with an error", bounds[0], bounds[1])
+				assert.ErrorIs(t, err, ErrIndex)
+			}()
+			ReverseRange([]int{0, 1, 2, 3, 4}, bounds[0], bounds[1])
+		}()
 	}
 }
 
@@ -773,18 +1302,44 @@ func TestAny(t *testing.T) {
 	data := Ones(nItems)
 	pred := oprs.Is(2)
 	t.Run("false test", func(t *testing.T) {
-		if Any(Cast(pred, data)...) {
+		if Any(Cast(pred, data)) {
 			oracle.Inequiv(t, 0, false, true)
 		}
 	})
 	t.Run("true test", func(t *testing.T) {
 		data = append(data, 2)
-		if !Any(Cast(pred, data)...) {
+		if !Any(Cast(pred, data)) {
 			oracle.Inequiv(t, 0, true, false)
 		}
 	})
 }
 
+func TestAllAnyConsistency(t *testing.T) {
+	assert.True(t, All([]bool{}))
+	assert.False(t, Any([]bool{}))
+	assert.True(t, AllFunc(oprs.Is(1), []int{}))
+	assert.False(t, AnyFunc(oprs.Is(1), []int{}))
+
+	large := Repeat(true, 10_000_000)
+	assert.True(t, All(large))
+	large[len(large)-1] = false
+	assert.False(t, All(large))
+	assert.True(t, Any(large))
+
+	calls := 0
+	counting := func(i int) bool {
+		calls++
+		return i < 3
+	}
+	data := Upton[int](10)
+	assert.False(t, AllFunc(counting, data))
+	assert.Equal(t, 4, calls, "AllFunc should stop at the first failure")
+
+	calls = 0
+	assert.True(t, AnyFunc(func(i int) bool { return !counting(i) }, data))
+	assert.Equal(t, 4, calls, "AnyFunc should stop at the first success")
+}
+
 func TestMax(t *testing.T) {
 	data := Upton[int](2)
 	t.Run("basic", func(t *testing.T) {
@@ -835,6 +1390,39 @@ func TestMin(t *testing.T) {
 	})
 }
 
+func TestClamp(t *testing.T) {
+	type test struct {
+		v, want int
+	}
+	tests := []test{{-5, 0}, {0, 0}, {3, 3}, {10, 10}, {11, 10}}
+	for _, test := range tests {
+		if have := Clamp(0, 10, test.v); have != test.want {
+			t.Errorf("Clamp(0, 10, %d) = %d, want %d", test.v, have, test.want)
+		}
+	}
+	assert.Equal(t, 2.5, Clamp(2.5, 2.5, 7.0))
+	assert.Equal(t, "b", Clamp("b", "d", "a"))
+
+	s := []float64{-1, 0.5, 2}
+	assert.Equal(t, []float64{0, 0.5, 1}, ClampSlice(0, 1, s))
+	assert.Equal(t, []float64{-1, 0.5, 2}, s, "ClampSlice should not modify its argument")
+	assert.Equal(t, []int{}, ClampSlice(0, 1, []int{}))
+
+	for name, f := range map[string]func(){
+		"Clamp":      func() { Clamp(1, 0, 0) },
+		"ClampSlice": func() { ClampSlice(1, 0, []int{}) },
+	} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "%s should panic with an error when lo > hi", name)
+				assert.ErrorIs(t, err, ErrBounds)
+			}()
+			f()
+		}()
+	}
+}
+
 func TestExtremal(t *testing.T) {
 	t.Run("lt ==> Min", func(t *testing.T) {
 		for i := range Upton[int](nTests) {
@@ -906,6 +1494,53 @@ func TestReduce(t *testing.T) {
 	}
 }
 
+func TestSum(t *testing.T) {
+	assert.Equal(t, 0, Sum([]int{}))
+	assert.Equal(t, 1, Prod([]int{}))
+	assert.Equal(t, 7, Sum([]int{7}))
+	assert.Equal(t, 7, Prod([]int{7}))
+	assert.Equal(t, 10, Sum([]int{1, 2, 3, 4}))
+	assert.Equal(t, 24, Prod([]int{1, 2, 3, 4}))
+
+	assert.Equal(t, 0.0, Sum([]float64(nil)))
+	assert.Equal(t, 1.0, Prod([]float64(nil)))
+	assert.Equal(t, 2.5, Sum([]float64{2.5}))
+	assert.Equal(t, 2.5, Prod([]float64{2.5}))
+	assert.Equal(t, 4.0, Sum([]float64{0.5, 1.5, 2}))
+	assert.Equal(t, 1.5, Prod([]float64{0.5, 1.5, 2}))
+
+	for i := range Upton[int](nTests) {
+		data := oracle.Mkr(nItems, nMax)
+		assert.Equal(t, Reduce(real.Add[int], data), Sum(data), "#%d", i)
+		assert.Equal(t, Reduce(real.Mul[int], data), Prod(data), "#%d", i)
+	}
+
+	bytes := Repeat(uint8(200), 3)
+	assert.Equal(t, uint8(88), Sum(bytes))
+	assert.Equal(t, 600, SumAs[uint8, int](bytes))
+	assert.Equal(t, 0, SumAs[uint8, int](nil))
+}
+
+func TestCumSum(t *testing.T) {
+	assert.Equal(t, []int{1, 3, 6, 10}, CumSum([]int{1, 2, 3, 4}))
+	assert.Equal(t, []int{1, 2, 6, 24}, CumProd([]int{1, 2, 3, 4}))
+	assert.Equal(t, []float64{0.5, 2, 4}, CumSum([]float64{0.5, 1.5, 2}))
+	assert.Equal(t, []float64{0.5, 0.75, 1.5}, CumProd([]float64{0.5, 1.5, 2}))
+	assert.Equal(t, []int{}, CumSum([]int{}))
+	assert.Equal(t, []float64{}, CumProd([]float64(nil)))
+
+	for i := range Upton[int](nTests) {
+		data := oracle.Mkr(nItems, nMax)
+		sums, prods := CumSum(data), CumProd(data)
+		require.Len(t, sums, len(data), "#%d", i)
+		require.Len(t, prods, len(data), "#%d", i)
+		for j := range data {
+			assert.Equal(t, Sum(data[:j+1]), sums[j], "#%d.%d", i, j)
+			assert.Equal(t, Prod(data[:j+1]), prods[j], "#%d.%d", i, j)
+		}
+	}
+}
+
 func TestUpto(t *testing.T) {
 	type argSet struct {
 		start, stop, step int
@@ -1109,6 +1744,69 @@ func TestSplitAfter(t *testing.T) {
 	}
 }
 
+func TestSpan(t *testing.T) {
+	small := func(i int) bool { return i < 5 }
+	data := []int{1, 2, 3, 7, 4, 9}
+
+	prefix, rest := Span(small, data)
+	assert.Equal(t, []int{1, 2, 3}, prefix)
+	assert.Equal(t, []int{7, 4, 9}, rest, "the remainder should keep later matches")
+
+	prefix, rest = Span(func(int) bool { return true }, data)
+	assert.Equal(t, data, prefix)
+	assert.Empty(t, rest)
+
+	prefix, rest = Span(func(int) bool { return false }, data)
+	assert.Empty(t, prefix)
+	assert.Equal(t, data, rest)
+
+	prefix, rest = Span(small, []int{})
+	assert.Empty(t, prefix)
+	assert.Empty(t, rest)
+
+	calls := 0
+	Span(func(i int) bool { calls++; return small(i) }, data)
+	assert.Equal(t, 4, calls, "Span should stop at the first failure")
+}
+
+func TestChunkBy(t *testing.T) {
+	id := func(i int) int { return i }
+	assert.Equal(t, [][]int{{1, 1}, {2, 2, 2}, {1}}, ChunkBy(id, []int{1, 1, 2, 2, 2, 1}), "only consecutive keys should be grouped")
+	assert.Equal(t, [][]int{{1, 1, 1}, {2, 2, 2}}, ChunkBy(id, Sorted([]int{1, 1, 2, 2, 2, 1})))
+	assert.Equal(t, [][]int{{1}, {2}, {3}}, ChunkBy(id, []int{1, 2, 3}))
+	assert.Equal(t, [][]int{{7}}, ChunkBy(id, []int{7}))
+	assert.Equal(t, [][]int{}, ChunkBy(id, []int{}))
+
+	parity := func(i int) bool { return i%2 == 0 }
+	data := []int{2, 4, 1, 3, 5, 6}
+	chunks := ChunkBy(parity, data)
+	assert.Equal(t, [][]int{{2, 4}, {1, 3, 5}, {6}}, chunks)
+	_ = append(chunks[0], 0)
+	assert.Equal(t, []int{2, 4, 1, 3, 5, 6}, data, "appending to a run should not overwrite its neighbour")
+}
+
+func TestSplitAt(t *testing.T) {
+	type test struct {
+		index       int
+		left, right []int
+	}
+	data := []int{0, 1, 2, 3}
+	tests := []test{
+		{index: 0, left: []int{}, right: []int{0, 1, 2, 3}},
+		{index: 1, left: []int{0}, right: []int{1, 2, 3}},
+		{index: 4, left: []int{0, 1, 2, 3}, right: []int{}},
+		{index: 5, left: []int{0, 1, 2, 3}, right: []int{}},
+		{index: -1, left: []int{0, 1, 2}, right: []int{3}},
+		{index: -4, left: []int{}, right: []int{0, 1, 2, 3}},
+		{index: -5, left: []int{}, right: []int{0, 1, 2, 3}},
+	}
+	for i, test := range tests {
+		left, right := SplitAt(test.index, data)
+		assert.Equal(t, test.left, left, "#%d: left", i)
+		assert.Equal(t, test.right, right, "#%d: right", i)
+	}
+}
+
 func TestRotated(t *testing.T) {
 	type test struct {
 		slice []int
@@ -1123,12 +1821,12 @@ func TestRotated(t *testing.T) {
 		{slice: []int{0, 1}, steps: -1, want: []int{1, 0}},
 		{slice: []int{0, 1}, steps: -2, want: []int{0, 1}},
 		{slice: []int{0, 1}, steps: -3, want: []int{1, 0}},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
-		{slice: Upton[int](rand.Int() % rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
+		{slice: Upton[int](rand.Intn(917)), steps: randSign(rand.Int())},
 	}
 	for i, test := range tests {
 		have := Rotated(test.slice, test.steps)
@@ -1137,7 +1835,49 @@ func TestRotated(t *testing.T) {
 			assert.Equal(t, have, test.want, "#%d: value failure", i)
 
 		}
-		assert.Equal(t, Rotated(have, -test.steps), test.slice, "#%d: value failure", i)
+		// an empty slice may be nil, but Rotated always returns a non-nil one
+		if back := Rotated(have, -test.steps); !Equal(back, test.slice) {
+			t.Errorf("#%d: round trip failure: %v, want %v", i, back, test.slice)
+		}
+	}
+	t.Run("no aliasing", func(t *testing.T) {
+		backing := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		slice := backing[:4]
+		for steps := -5; steps <= 5; steps++ {
+			have := Rotated(slice, steps)
+			assert.Equal(t, []int{0, 1, 2, 3}, slice, "steps %d: argument was modified", steps)
+			assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, backing, "steps %d: backing array was modified", steps)
+			assert.Equal(t, slice, Rotated(have, -steps), "steps %d: round trip failure", steps)
+		}
+	})
+}
+
+func TestRotate(t *testing.T) {
+	data := []int{0, 1, 2, 3, 4}
+	for _, steps := range []int{0, 1, 2, -1, -2, 5, 7, -7, 12} {
+		have := Clone(data)
+		Rotate(have, steps)
+		assert.Equal(t, Rotated(data, steps), have, "steps %d", steps)
+	}
+	for i := range Upton[int](nTests) {
+		data := oracle.Mkr(rand.Intn(nItems), nMax)
+		steps := randSign(rand.Intn(3 * nItems))
+		have := Clone(data)
+		Rotate(have, steps)
+		// Mkr may return nil, which Clone preserves but Rotated does not
+		if want := Rotated(data, steps); !Equal(want, have) {
+			t.Errorf("#%d: Rotate(%v, %d) gave %v, want %v", i, data, steps, have, want)
+		}
+	}
+	Rotate([]int{}, 3)
+}
+
+func BenchmarkRotate(b *testing.B) {
+	data := Upton[int](1e4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Rotate(data, 3333)
 	}
 }
 
@@ -1171,6 +1911,21 @@ func TestRepeat(t *testing.T) {
 	})
 }
 
+func TestRepeatShares(t *testing.T) {
+	seed := []int{1, 2, 3}
+	repeat := Repeat(seed, 3)
+	require.Len(t, repeat, 3)
+	repeat[0][0] = 7
+	for i, e := range repeat {
+		assert.Equal(t, []int{7, 2, 3}, e, "#%d should share seed's backing array", i)
+	}
+	assert.Equal(t, 7, seed[0])
+
+	tee := Tee(seed, 3)
+	tee[0][0] = 1
+	assert.Equal(t, []int{7, 2, 3}, tee[1], "Tee's copies should be independent")
+}
+
 func TestTee(l *testing.T) {
 	// tested under TestRepeat
 	l.Run("chain zero != self", func(l *testing.T) {
@@ -1266,6 +2021,42 @@ func TestGetxy(l *testing.T) {
 	}
 }
 
+func TestGetxySafe(t *testing.T) {
+	pix := Upton[int8](12) // 4 wide, 3 tall
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			have, ok := GetxySafe(pix, 4, x, y)
+			assert.True(t, ok, "x, y := %d, %d", x, y)
+			assert.Equal(t, int8(y*4+x), have, "x, y := %d, %d", x, y)
+		}
+	}
+	for _, xy := range [][2]int{{4, 0}, {-1, 1}, {0, -1}, {0, 3}, {5, 2}} {
+		have, ok := GetxySafe(pix, 4, xy[0], xy[1])
+		assert.False(t, ok, "x, y := %d, %d", xy[0], xy[1])
+		assert.Zero(t, have)
+	}
+}
+
+func TestSetxy(t *testing.T) {
+	pix := make([]int, 6) // 3 wide, 2 tall
+	Setxy(pix, 3, 2, 0, 7)
+	Setxy(pix, 3, 1, 1, 9)
+	assert.Equal(t, []int{0, 0, 7, 0, 9, 0}, pix)
+	assert.Equal(t, 9, Getxy(pix, 3, 1, 1))
+
+	for _, xy := range [][2]int{{3, 0}, {0, 2}, {-1, 0}} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				require.True(t, ok, "Setxy(%d, %d) should panic with an error", xy[0], xy[1])
+				assert.ErrorIs(t, err, ErrIndex)
+			}()
+			Setxy(pix, 3, xy[0], xy[1], 1)
+		}()
+	}
+	assert.Equal(t, []int{0, 0, 7, 0, 9, 0}, pix)
+}
+
 func TestWindows(t *testing.T) {
 	arg := Upton[int](10)
 	wants := map[int][][]int{
@@ -1287,6 +2078,454 @@ func TestWindows(t *testing.T) {
 	}
 }
 
+func TestWindowsStep(t *testing.T) {
+	arg := Upton[int](10)
+	for size := 0; size <= len(arg)+1; size++ {
+		assert.Equal(t, Windows(arg, size), WindowsStep(size, 1, arg), "step 1, size %d", size)
+	}
+	for _, size := range []int{1, 2, 5, 10} {
+		assert.Equal(t, Snap(size, arg), WindowsStep(size, size, arg), "step %[1]d, size %[1]d", size)
+	}
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}}, WindowsStep(3, 3, arg))
+	assert.Equal(t, [][]int{{0, 1}, {4, 5}, {8, 9}}, WindowsStep(2, 4, arg))
+	assert.Equal(t, [][]int{{0}, {5}}, WindowsStep(1, 5, arg))
+	assert.Empty(t, WindowsStep(0, 1, arg))
+	assert.Empty(t, WindowsStep(2, 0, arg))
+	assert.Empty(t, WindowsStep(2, -1, arg))
+	assert.Empty(t, WindowsStep(11, 1, arg))
+}
+
+func TestSnapWindowsString(t *testing.T) {
+	assert.Equal(t, []string{"ab", "cd", "e"}, SnapString(2, "abcde"))
+	assert.Equal(t, []string{"ab", "bc", "cd", "de"}, WindowsString(2, "abcde"))
+
+	emoji := "a😀b🎉c"
+	assert.Equal(t, []string{"a😀", "b🎉", "c"}, SnapString(2, emoji))
+	assert.Equal(t, []string{"a😀", "😀b", "b🎉", "🎉c"}, WindowsString(2, emoji))
+	assert.Equal(t, []string{"hé", "ll", "o"}, SnapString(2, "héllo"))
+	assert.Equal(t, []string{emoji}, SnapString(10, emoji))
+	assert.Equal(t, []string{emoji}, WindowsString(5, emoji))
+
+	for _, size := range []int{1, 3, 5} {
+		runes := []rune(emoji)
+		assert.Equal(t, Cast(func(r []rune) string { return string(r) }, Snap(size, runes)), SnapString(size, emoji), "size %d", size)
+		assert.Equal(t, Cast(func(r []rune) string { return string(r) }, Windows(runes, size)), WindowsString(size, emoji), "size %d", size)
+	}
+
+	assert.Empty(t, SnapString(0, emoji))
+	assert.Empty(t, SnapString(2, ""))
+	assert.Empty(t, WindowsString(0, emoji))
+	assert.Empty(t, WindowsString(6, emoji))
+}
+
+func TestWindowReduce(t *testing.T) {
+	data := oracle.Mkr(nItems, nMax)
+	for size := 0; size <= len(data)+1; size++ {
+		var want []int
+		var wantMean []float64
+		for _, window := range Windows(data, size) {
+			sum := 0
+			for _, e := range window {
+				sum += e
+			}
+			want = append(want, sum)
+			wantMean = append(wantMean, float64(sum)/float64(size))
+		}
+		assert.Equal(t, want, WindowReduce(size, real.Add[int], 0, data), "WindowReduce: size %d", size)
+		have := MovingSum(size, data)
+		if len(want) == 0 {
+			assert.Equal(t, 0, len(have), "MovingSum: size %d", size)
+			continue
+		}
+		assert.Equal(t, want, have, "MovingSum: size %d", size)
+		mean := MovingMean(size, data)
+		assert.Equal(t, len(wantMean), len(mean), "MovingMean: size %d", size)
+		for i := range mean {
+			if math.Abs(mean[i]-wantMean[i]) > 1e-9 {
+				oracle.Quitf(t, "MovingMean: size %d, window %d: have %v, want %v", size, i, mean[i], wantMean[i])
+			}
+		}
+	}
+
+	floats := []float64{0.1, 0.2, 0.3, 1e16, 1, 2}
+	assert.Equal(t, WindowReduce(2, real.Add[float64], 0, floats), MovingSum(2, floats))
+	mean := MovingMean(2, floats)
+	require.Len(t, mean, 5)
+	assert.InDelta(t, 0.15, mean[0], 1e-12)
+	assert.InDelta(t, 0.25, mean[1], 1e-12)
+	assert.Equal(t, 1.5, mean[4], "precision should survive a large value leaving the window")
+	assert.Nil(t, MovingSum(3, []float64{1, 2}))
+}
+
+func TestCountPred(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	assert.Equal(t, uint(0), CountPred(even, []int{}))
+	assert.Equal(t, uint(0), CountPred(even, []int{1, 3, 5}))
+	assert.Equal(t, uint(3), CountPred(even, []int{0, 1, 2, 3, 4, 5}))
+
+	type person struct {
+		name string
+		age  int
+	}
+	people := []person{{"ann", 31}, {"bob", 17}, {"cat", 45}, {"dan", 17}}
+	assert.Equal(t, uint(2), CountPred(func(p person) bool { return p.age == 17 }, people))
+	assert.Equal(t, uint(1), CountPred(func(p person) bool { return p.name == "cat" }, people))
+}
+
+func TestSplit2(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	yes, no := Split2(even, []int{5, 2, 3, 8, 4, 1})
+	assert.Equal(t, []int{2, 8, 4}, yes)
+	assert.Equal(t, []int{5, 3, 1}, no)
+
+	yes, no = Split2(even, []int{2, 4, 6})
+	assert.Equal(t, []int{2, 4, 6}, yes)
+	assert.Empty(t, no)
+
+	yes, no = Split2(even, []int{1, 3})
+	assert.Empty(t, yes)
+	assert.Equal(t, []int{1, 3}, no)
+
+	yes, no = Split2(even, []int{})
+	assert.Empty(t, yes)
+	assert.Empty(t, no)
+}
+
+func TestDedupeReduce(t *testing.T) {
+	type record struct {
+		id  string
+		qty int
+	}
+	id := func(r record) string { return r.id }
+	sum := func(a, b record) record { return record{a.id, a.qty + b.qty} }
+
+	assert.Empty(t, DedupeReduce(id, sum, []record{}))
+
+	records := []record{{"b", 1}, {"a", 2}, {"b", 3}, {"c", 4}, {"a", 5}, {"b", 6}}
+	have := DedupeReduce(id, sum, records)
+	assert.Equal(t, []record{{"b", 10}, {"a", 7}, {"c", 4}}, have)
+	assert.Len(t, have, len(Compacted(Sorted(Cast(id, records)))))
+	assert.Equal(t, record{"b", 1}, records[0], "input was modified")
+}
+
+func TestAssociate(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+	users := []user{{1, "ann"}, {2, "bob"}, {1, "amy"}}
+	idName := func(u user) (int, string) { return u.id, u.name }
+	id := func(u user) int { return u.id }
+
+	assert.Equal(t, map[int]string{1: "amy", 2: "bob"}, Associate(idName, users), "the last value should win")
+	assert.Equal(t, map[int]user{1: {1, "amy"}, 2: {2, "bob"}}, AssociateBy(id, users), "the last value should win")
+	assert.Equal(t, map[string]int{"ann": 1, "bob": 2, "amy": 1}, Associate(func(u user) (string, int) { return u.name, u.id }, users))
+
+	assert.Empty(t, Associate(idName, []user{}))
+	assert.Empty(t, AssociateBy(id, nil))
+}
+
+func TestFrequencies(t *testing.T) {
+	assert.Equal(t, map[string]int{"a": 2, "b": 1, "c": 3}, Frequencies([]string{"a", "c", "a", "b", "c", "c"}))
+	assert.Equal(t, map[string]int{}, Frequencies([]string{}))
+
+	length := func(s string) int { return len(s) }
+	assert.Equal(t, map[int]int{1: 2, 2: 1, 3: 1}, FrequenciesFunc(length, []string{"a", "bc", "d", "efg"}))
+	assert.Equal(t, map[int]int{}, FrequenciesFunc(length, nil))
+}
+
+func TestTopFrequencies(t *testing.T) {
+	arg := []string{"a", "a", "b", "c", "c", "c"}
+	assert.Equal(t, []LR[string, int]{{"c", 3}, {"a", 2}}, TopFrequencies(2, arg))
+	assert.Equal(t, []LR[string, int]{{"c", 3}, {"a", 2}, {"b", 1}}, TopFrequencies(5, arg))
+	assert.Empty(t, TopFrequencies(0, arg))
+	assert.Empty(t, TopFrequencies(-1, arg))
+	assert.Empty(t, TopFrequencies(3, []string{}))
+	assert.Equal(t, []LR[int, int]{{3, 2}, {1, 2}, {2, 1}}, TopFrequencies(3, []int{3, 1, 2, 1, 3}))
+}
+
+func TestCastIndexed(t *testing.T) {
+	arg := []string{"a", "b", "c"}
+	var seen []int
+	have := CastIndexed(func(i int, e string) string {
+		seen = append(seen, i)
+		return fmt.Sprintf("%d: %s", i, e)
+	}, arg)
+	assert.Equal(t, []string{"0: a", "1: b", "2: c"}, have)
+	assert.Equal(t, []int{0, 1, 2}, seen)
+
+	for _, n := range []int{0, 1, 20} {
+		arg := oracle.RandNums[int](n)
+		assert.Len(t, CastIndexed(func(i, e int) int { return i * e }, arg), n)
+	}
+}
+
+func TestChooseWeighted(t *testing.T) {
+	_, err := ChooseWeighted([]float64{1, 2}, []int{1})
+	assert.ErrorIs(t, err, ErrLength)
+	_, err = ChooseWeighted([]float64{0, 0}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrWeight)
+	_, err = ChooseWeighted([]float64{1, -1}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrWeight)
+	_, err = ChooseWeighted([]float64{}, []int{})
+	assert.ErrorIs(t, err, ErrWeight)
+	_, err = ChooseWeighted([]float64{1, math.Inf(1)}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrWeight)
+	_, err = ChooseWeighted([]float64{1, math.NaN()}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrWeight)
+	_, err = ChooseWeighted([]float64{math.MaxFloat64, math.MaxFloat64}, []int{1, 2})
+	assert.ErrorIs(t, err, ErrWeight, "weights summing to infinity should be rejected")
+
+	// a variate which rounds up to the total must not run past the last positive weight
+	for _, u := range []float64{math.Nextafter(1, 0), 1} {
+		e, err := chooseWeighted(u, []float64{1, 2, 0, 0}, []int{0, 1, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, 1, e, "u = %v", u)
+	}
+	e, err := chooseWeighted(0, []float64{0, 0, 3}, []int{0, 1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, e, "zero weights should never be chosen")
+
+	const draws = 100_000
+	weights := []float64{1, 0, 2, 3, 4}
+	counts := make([]int, len(weights))
+	for i := 0; i < draws; i++ {
+		e, err := ChooseWeighted(weights, []int{0, 1, 2, 3, 4})
+		require.NoError(t, err)
+		counts[e]++
+	}
+	assert.Equal(t, 0, counts[1], "zero weights should never be chosen")
+	for i, w := range weights {
+		assert.InDelta(t, w/10, float64(counts[i])/draws, 0.01, "frequency of #%d", i)
+	}
+}
+
+func TestSample(t *testing.T) {
+	arg := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	orig := Clone(arg)
+	assert.Empty(t, Sample(0, arg))
+	assert.Empty(t, Sample(-1, arg))
+	assert.Empty(t, Sample(3, []int{}))
+	assert.ElementsMatch(t, arg, Sample(len(arg)+5, arg))
+
+	const runs, k = 20_000, 3
+	counts := make([]int, len(arg))
+	for i := 0; i < runs; i++ {
+		have := Sample(k, arg)
+		require.Len(t, have, k)
+		assert.Len(t, Compacted(Sorted(have)), k, "duplicates in %v", have)
+		for _, e := range have {
+			counts[e]++
+		}
+	}
+	assert.Equal(t, orig, arg, "input was modified")
+	for e, c := range counts {
+		assert.InDelta(t, float64(k)/float64(len(arg)), float64(c)/runs, 0.02, "inclusion frequency of %d", e)
+	}
+}
+
+func TestPipe(t *testing.T) {
+	inc := func(i int) int { return i + 1 }
+	double := func(i int) int { return i * 2 }
+
+	assert.Equal(t, 8, Pipe(3, inc, double), "functions should apply from left to right")
+	assert.Equal(t, 7, Pipe(3, double, inc), "functions should apply from left to right")
+	assert.Equal(t, 3, Pipe(3))
+	assert.Equal(t, 20, Pipe(3, inc, inc, double, double, inc, func(i int) int { return i - 1 }))
+
+	assert.Equal(t, 8, Compose(inc, double)(3))
+	assert.Equal(t, 7, Compose(double, inc)(3))
+	assert.Equal(t, 3, Compose[int]()(3))
+	assert.Equal(t, []int{2, 4, 6}, Cast(Compose(inc, double), []int{0, 1, 2}))
+}
+
+func TestShuffleRand(t *testing.T) {
+	arg := oracle.RandNums[int](50)
+	first := ShuffleRand(rand.New(rand.NewSource(7)), arg)
+	second := ShuffleRand(rand.New(rand.NewSource(7)), arg)
+	assert.Equal(t, first, second)
+	assert.ElementsMatch(t, arg, first)
+	assert.ElementsMatch(t, arg, Shuffle(arg))
+
+	r1, r2 := rand.New(rand.NewSource(7)), rand.New(rand.NewSource(7))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, ChooseRand(r1, arg), ChooseRand(r2, arg))
+	}
+}
+
+func TestShuffleInPlace(t *testing.T) {
+	arg := oracle.RandNums[int](50)
+	have := Clone(arg)
+	ShuffleInPlace(have)
+	assert.ElementsMatch(t, arg, have)
+
+	first, second := Clone(arg), Clone(arg)
+	ShuffleInPlaceRand(rand.New(rand.NewSource(7)), first)
+	ShuffleInPlaceRand(rand.New(rand.NewSource(7)), second)
+	assert.Equal(t, first, second)
+	assert.ElementsMatch(t, arg, first)
+
+	ShuffleInPlace([]int{})
+}
+
+func BenchmarkShuffleInPlace(b *testing.B) {
+	s := makeRandomInts(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ShuffleInPlace(s)
+	}
+}
+
+func TestMode(t *testing.T) {
+	mode, count, ok := Mode([]string{"a", "c", "b", "c"})
+	assert.True(t, ok)
+	assert.Equal(t, "c", mode)
+	assert.Equal(t, 2, count)
+
+	mode, count, ok = Mode([]string{"b", "a", "a", "b"})
+	assert.True(t, ok)
+	assert.Equal(t, "b", mode, "ties should be won by the first to appear")
+	assert.Equal(t, 2, count)
+
+	mode, count, ok = Mode([]string{})
+	assert.False(t, ok)
+	assert.Equal(t, "", mode)
+	assert.Equal(t, 0, count)
+
+	arg := []int{4, 1, 2, 2, 3, 3, 3, 1}
+	assert.Equal(t, []int{3, 1}, ModeN(2, arg))
+	assert.Equal(t, []int{3, 1, 2, 4}, ModeN(10, arg))
+	assert.Empty(t, ModeN(0, arg))
+	assert.Empty(t, ModeN(2, []int{}))
+}
+
+func TestDiff(t *testing.T) {
+	split := func(s string) []string { return strings.Split(s, "") }
+	a, b := split("abcabba"), split("cbabac")
+	script := Diff(a, b)
+
+	kept, deleted, inserted := []string{}, 0, 0
+	for _, edit := range script {
+		switch edit.Op {
+		case EditKeep:
+			kept = append(kept, edit.Value)
+		case EditDelete:
+			deleted++
+		case EditInsert:
+			inserted++
+		}
+	}
+	assert.Len(t, kept, 4, "a longest common subsequence should be kept")
+	assert.True(t, IsSubsequence(kept, a))
+	assert.True(t, IsSubsequence(kept, b))
+	assert.Equal(t, len(a)-4, deleted)
+	assert.Equal(t, len(b)-4, inserted)
+
+	have, err := ApplyDiff(a, script)
+	require.NoError(t, err)
+	assert.Equal(t, b, have)
+
+	assert.Equal(t, []Edit[string]{
+		{EditKeep, "x"}, {EditDelete, "y"}, {EditInsert, "w"}, {EditKeep, "z"},
+	}, Diff(split("xyz"), split("xwz")))
+
+	for _, pair := range [][2]string{{"", ""}, {"", "abc"}, {"abc", ""}, {"abc", "abc"}, {"abc", "def"}} {
+		a, b := split(pair[0]), split(pair[1])
+		have, err := ApplyDiff(a, Diff(a, b))
+		require.NoError(t, err, "%q -> %q", pair[0], pair[1])
+		assert.Equal(t, b, have, "%q -> %q", pair[0], pair[1])
+	}
+
+	_, err = ApplyDiff(split("abc"), Diff(split("abd"), split("xyz")))
+	assert.ErrorIs(t, err, ErrEdit)
+	_, err = ApplyDiff(split("abc"), Diff(split("ab"), split("ab")))
+	assert.ErrorIs(t, err, ErrEdit)
+	assert.Equal(t, "Insert", EditInsert.String())
+}
+
+func TestLCS(t *testing.T) {
+	split := func(s string) []string { return strings.Split(s, "") }
+	tests := []struct {
+		a, b, want string
+	}{
+		{"", "", ""},
+		{"abc", "", ""},
+		{"abc", "def", ""},
+		{"abc", "abc", "abc"},
+		{"abcd", "acbd", "abd"},
+		{"abcd", "bcda", "bcd"},
+		{"xmjyauz", "mzjawxu", "mjau"},
+	}
+	assert.Equal(t, split("mjau"), LCS(split("xmjyauz"), split("mzjawxu")))
+	for _, test := range tests {
+		have := LCS(split(test.a), split(test.b))
+		assert.Len(t, have, len(test.want), "LCS(%q, %q) = %q, want one like %q", test.a, test.b, have, test.want)
+		assert.True(t, IsSubsequence(have, split(test.a)))
+		assert.True(t, IsSubsequence(have, split(test.b)))
+	}
+}
+
+func TestCommonPrefixSuffix(t *testing.T) {
+	tests := []struct {
+		a, b           []int
+		prefix, suffix []int
+	}{
+		{nil, nil, []int{}, []int{}},
+		{[]int{1, 2, 3}, nil, []int{}, []int{}},
+		{[]int{1, 2, 3}, []int{4, 5, 6}, []int{}, []int{}},
+		{[]int{1, 2, 3}, []int{1, 2, 3}, []int{1, 2, 3}, []int{1, 2, 3}},
+		{[]int{1, 2, 3, 4}, []int{1, 2, 5, 4}, []int{1, 2}, []int{4}},
+		{[]int{1, 2}, []int{1, 2, 3, 1, 2}, []int{1, 2}, []int{1, 2}},
+	}
+	for _, test := range tests {
+		if have := CommonPrefix(test.a, test.b); !Equal(have, test.prefix) {
+			t.Errorf("CommonPrefix(%v, %v) = %v, want %v", test.a, test.b, have, test.prefix)
+		}
+		if have := CommonSuffix(test.a, test.b); !Equal(have, test.suffix) {
+			t.Errorf("CommonSuffix(%v, %v) = %v, want %v", test.a, test.b, have, test.suffix)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	grown := Resize([]int{1, 2, 3}, 2, 3)
+	assert.Len(t, grown, 6)
+	assert.Equal(t, []int{1, 2, 3, 0, 0, 0}, grown)
+
+	shrunk := Resize([]int{1, 2, 3, 4, 5, 6}, 3)
+	assert.Len(t, shrunk, 3)
+	assert.Equal(t, []int{1, 2, 3}, shrunk)
+
+	assert.Equal(t, []int{1, 2, 3, 4}, Resize([]int{1, 2, 3, 4}, 2, 2))
+	assert.Equal(t, []int{1, 2}, Resize([]int{1, 2}))
+	assert.Empty(t, Resize([]int{1, 2}, 0))
+}
+
+func TestCastAsyncErr(t *testing.T) {
+	errOdd := fmt.Errorf("odd")
+	half := func(i int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		if i%2 == 1 {
+			return 0, fmt.Errorf("%d: %w", i, errOdd)
+		}
+		return i / 2, nil
+	}
+
+	have, err := CastAsyncErr(half, []int{0, 2, 4, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, have)
+
+	have, err = CastAsyncErr(half, []int{2, 3, 4, 5, 6})
+	assert.ErrorIs(t, err, errOdd)
+	assert.EqualError(t, err, "3: odd", "the earliest error should be returned")
+	assert.Equal(t, []int{1, 0, 2, 0, 3}, have)
+
+	have, err = CastAsyncErr(half, []int{})
+	assert.NoError(t, err)
+	assert.Empty(t, have)
+}
+
 func TestEnumerate(t *testing.T) {
 	type check struct {
 		arg []int
@@ -1307,6 +2546,49 @@ func TestEnumerate(t *testing.T) {
 	}
 }
 
+func TestEnumerateCapture(t *testing.T) {
+	arg := []string{"a", "b", "c"}
+	for i, f := range Enumerate[int](arg) {
+		k, e := f()
+		assert.Equal(t, i, k, "#%d: index violation", i)
+		assert.Equal(t, arg[i], e, "#%d: elem violation", i)
+	}
+}
+
+func TestEnumerated(t *testing.T) {
+	assert.Len(t, Enumerated[int]([]int{}), 0)
+	for _, arg := range [][]int{{7}, {3, 1, 4, 1, 5}, oracle.RandNums[int](20)} {
+		have := Enumerated[uint8](arg)
+		require.Len(t, have, len(arg))
+		for i, pair := range have {
+			assert.Equal(t, uint8(i), pair.Left, "#%d: index violation", i)
+			assert.Equal(t, arg[i], pair.Right, "#%d: elem violation", i)
+		}
+	}
+}
+
+func TestTeeChan(t *testing.T) {
+	arg := oracle.RandNums[int](20)
+	chans := TeeChan(3, arg)
+	require.Len(t, chans, 3)
+	for i := len(chans) - 1; i >= 0; i-- {
+		have := []int{}
+		for e := range chans[i] {
+			have = append(have, e)
+		}
+		assert.Equal(t, arg, have, "channel #%d", i)
+		_, ok := <-chans[i]
+		assert.False(t, ok, "channel #%d should be closed", i)
+	}
+
+	for _, ch := range TeeChan(2, []int{}) {
+		_, ok := <-ch
+		assert.False(t, ok)
+	}
+	assert.Empty(t, TeeChan(0, arg))
+	assert.Empty(t, TeeChan(-1, arg))
+}
+
 func TestWalks(t *testing.T) {
 	type check struct {
 		slice  []int
@@ -1328,3 +2610,144 @@ func TestPairwise(t *testing.T) {
 	require.Equal(t, [][]byte{{'A', 'B'}, {'B', 'C'}, {'C', 'D'}, {'D', 'E'}, {'E', 'F'}, {'F', 'G'}}, Pairwise([]byte("ABCDEFG")...))
 	require.Equal(t, [][]rune{{'A', 'B'}, {'B', 'C'}, {'C', 'D'}, {'D', 'E'}, {'E', 'F'}, {'F', 'G'}}, Pairwise([]rune("ABCDEFG")...))
 }
+
+func TestStats(t *testing.T) {
+	twoPass := func(data []float64) (mean, variance float64) {
+		for _, e := range data {
+			mean += e
+		}
+		mean /= float64(len(data))
+		for _, e := range data {
+			variance += (e - mean) * (e - mean)
+		}
+		return mean, variance / float64(len(data))
+	}
+	t.Run("empty", func(t *testing.T) {
+		s := NewStats([]float64{})
+		assert.Equal(t, 0, s.Count())
+		assert.Equal(t, 0.0, s.Mean())
+		assert.Equal(t, 0.0, s.Variance())
+		assert.Equal(t, 0.0, s.Min())
+		assert.Equal(t, 0.0, s.Max())
+	})
+	t.Run("single", func(t *testing.T) {
+		s := NewStats([]int{7})
+		assert.Equal(t, 1, s.Count())
+		assert.Equal(t, 7.0, s.Mean())
+		assert.Equal(t, 0.0, s.Variance())
+		assert.Equal(t, 7, s.Min())
+		assert.Equal(t, 7, s.Max())
+	})
+	t.Run("random", func(t *testing.T) {
+		for i := range Upton[int](nTests) {
+			data := make([]float64, rand.Intn(nMax*nMax)+1)
+			for j := range data {
+				data[j] = 1e6 + rand.NormFloat64()
+			}
+			s := NewStats(data)
+			mean, variance := twoPass(data)
+			assert.Equal(t, len(data), s.Count(), "#%d: count", i)
+			assert.Equal(t, data[Min(data...)], s.Min(), "#%d: min", i)
+			assert.Equal(t, data[Max(data...)], s.Max(), "#%d: max", i)
+			if math.Abs(mean-s.Mean()) > 1e-6 {
+				oracle.Quitf(t, "#%d: mean: have %v, want %v", i, s.Mean(), mean)
+			}
+			if math.Abs(variance-s.Variance()) > 1e-6 {
+				oracle.Quitf(t, "#%d: variance: have %v, want %v", i, s.Variance(), variance)
+			}
+		}
+	})
+}
+
+func TestIntersperse(t *testing.T) {
+	assert.Equal(t, []int{}, Intersperse(0, []int{}))
+	assert.Equal(t, []int{1}, Intersperse(0, []int{1}))
+	assert.Equal(t, []int{1, 0, 2}, Intersperse(0, []int{1, 2}))
+	assert.Equal(t, []int{1, 0, 2, 0, 3}, Intersperse(0, []int{1, 2, 3}))
+
+	words := []string{"a", "b", "c"}
+	assert.Equal(t, JoinString(", ", words), strings.Join(Intersperse(", ", words), ""))
+	assert.Equal(t, []string{"a", "b", "c"}, words, "input was modified")
+}
+
+func TestJoinString(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"a"},
+		{"", ""},
+		strings.Split("quick brown dog jumps over the lazy fox", " "),
+	}
+	for i, test := range tests {
+		for _, sep := range []string{"", ", "} {
+			assert.Equal(t, strings.Join(test, sep), JoinString(sep, test), "#%d: %q", i, sep)
+			assert.Equal(t, strings.Join(test, sep), Join(test, sep), "#%d: %q", i, sep)
+		}
+	}
+}
+
+func BenchmarkJoinString(b *testing.B) {
+	for _, size := range []int{1e2, 1e3, 1e4} {
+		data := Repeat("iters", size)
+		b.Run(fmt.Sprintf("Join/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Join(data, ",")
+			}
+		})
+		b.Run(fmt.Sprintf("JoinString/%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				JoinString(",", data)
+			}
+		})
+	}
+}
+
+func TestPairwiseWrap(t *testing.T) {
+	type lr = LR[byte, byte]
+	require.Equal(t, []lr{{'A', 'B'}, {'B', 'C'}, {'C', 'A'}}, PairwiseWrap([]byte("ABC")))
+	require.Equal(t, []lr{{'A', 'A'}}, PairwiseWrap([]byte("A")))
+	require.Equal(t, []lr{}, PairwiseWrap([]byte{}))
+}
+
+func TestDeref(t *testing.T) {
+	one, two := 1, 2
+	assert.Equal(t, []int{1, 2}, Deref([]*int{&one, &two}))
+	assert.Equal(t, []int{}, Deref([]*int{}))
+	assert.Panics(t, func() { Deref([]*int{&one, nil}) })
+
+	var have []int
+	assert.NotPanics(t, func() { have = DerefOr(-1, []*int{nil, &one, nil, &two}) })
+	assert.Equal(t, []int{-1, 1, -1, 2}, have)
+	assert.Equal(t, []string{"", ""}, DerefOr("", []*string{nil, nil}))
+	assert.Equal(t, []int{}, DerefOr(0, nil))
+
+	one = 9
+	assert.Equal(t, -1, have[0], "DerefOr should copy the values")
+	assert.Equal(t, 1, have[1], "DerefOr should copy the values")
+}
+
+func TestUnify(t *testing.T) {
+	have, err := Unify[int]([]any{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, have)
+
+	data := []string{"a", "b"}
+	strs, err := Unify[string](Anify(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, strs)
+
+	have, err = Unify[int]([]any{1, "2", 3.0})
+	assert.ErrorIs(t, err, ErrType)
+	assert.EqualError(t, err, "element has the wrong type: element 1 is string, not int")
+	assert.Nil(t, have)
+
+	_, err = Unify[int]([]any{1, nil})
+	assert.EqualError(t, err, "element has the wrong type: element 1 is <nil>, not int")
+
+	errs, err := Unify[error]([]any{nil, ErrType})
+	require.NoError(t, err, "nil should convert to interface types")
+	assert.Equal(t, []error{nil, ErrType}, errs)
+
+	have, err = Unify[int]([]any{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{}, have)
+}
@@ -71,6 +71,35 @@ func SortStableKey[E any, O rules.Ordered](key func(E) O, data []E) {
 	SortStableFunc(k.Lt, data)
 }
 
+// SortCmp sorts the slice x in ascending order as determined by cmp, which
+// follows the same convention as BinarySearchFunc's, so that one comparison
+// function can drive both. This sort is not guaranteed to be stable.
+func SortCmp[E any](cmp func(E, E) int, x []E) {
+	SortFunc(func(a, b E) bool { return cmp(a, b) < 0 }, x)
+}
+
+// SortStableCmp sorts the slice x while keeping the original order of equal
+// elements, using cmp to compare elements. see SortCmp for more info
+func SortStableCmp[E any](cmp func(E, E) int, x []E) {
+	SortStableFunc(func(a, b E) bool { return cmp(a, b) < 0 }, x)
+}
+
+// CompareChain combines several comparison functions into one which defers to
+// each in turn, returning the first non-zero result, so that ties on one
+// criterion are broken by the next. No functions yields a comparison which
+// considers everything equal.
+// see BinarySearchFunc and Key.Cmp for more info
+func CompareChain[E any](cmps ...func(E, E) int) func(E, E) int {
+	return func(a, b E) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
 // IsSorted reports whether x is sorted in ascending order.
 func IsSorted[E rules.Ordered](x []E) bool {
 	for i := len(x) - 1; i > 0; i-- {
@@ -81,6 +110,18 @@ func IsSorted[E rules.Ordered](x []E) bool {
 	return true
 }
 
+// IsSortedUntil returns the index of the first element of x which is less than
+// its predecessor, or len(x) if x is sorted in ascending order.
+// x[:IsSortedUntil(x)] is the longest sorted prefix of x.
+func IsSortedUntil[E rules.Ordered](x []E) int {
+	for i := 1; i < len(x); i++ {
+		if x[i] < x[i-1] {
+			return i
+		}
+	}
+	return len(x)
+}
+
 // IsSortedFunc reports whether x is sorted in ascending order, with less as the
 // comparison function.
 func IsSortedFunc[E any](less func(a, b E) bool, x []E) bool {
@@ -134,6 +175,133 @@ func BinarySearchKey[E any, O rules.Ordered](key func(E) O, target E, space []E)
 	return BinarySearchFunc(k.Cmp, target, space)
 }
 
+// LowerBound returns the first index i at which space[i] >= target, or len(space)
+// if there is none. The slice must be sorted in increasing order.
+func LowerBound[E rules.Ordered](target E, space []E) int {
+	return search(len(space), func(i int) bool { return space[i] >= target })
+}
+
+// UpperBound returns the first index i at which space[i] > target, or len(space)
+// if there is none. The slice must be sorted in increasing order.
+func UpperBound[E rules.Ordered](target E, space []E) int {
+	return search(len(space), func(i int) bool { return space[i] > target })
+}
+
+// EqualRange returns the bounds of the half-open span, [lo, hi), of elements
+// equal to target in a slice sorted in increasing order.
+// If target is absent then lo == hi is the position at which it would appear.
+func EqualRange[E rules.Ordered](target E, space []E) (lo, hi int) {
+	lo = search(len(space), func(i int) bool { return space[i] >= target })
+	hi = lo + search(len(space)-lo, func(i int) bool { return space[lo+i] > target })
+	return lo, hi
+}
+
+// EqualRangeFunc works like EqualRange, but uses a custom comparison function.
+// see BinarySearchFunc for more info
+func EqualRangeFunc[E any](cmp func(E, E) int, target E, space []E) (lo, hi int) {
+	lo = search(len(space), func(i int) bool { return cmp(space[i], target) >= 0 })
+	hi = lo + search(len(space)-lo, func(i int) bool { return cmp(space[lo+i], target) > 0 })
+	return lo, hi
+}
+
+// EqualRangeKey accepts a measuring key and calls EqualRangeFunc
+func EqualRangeKey[E any, O rules.Ordered](key func(E) O, target E, space []E) (lo, hi int) {
+	k := Key[E, O](key)
+	return EqualRangeFunc(k.Cmp, target, space)
+}
+
+// InsertSorted inserts v into the sorted slice s at the position found by BinarySearch,
+// returning the modified slice, which remains sorted.
+// see Insert for details on how s is modified
+func InsertSorted[E rules.Ordered](s []E, v E) []E {
+	pos, _ := BinarySearch(v, s)
+	return Insert(s, pos, v)
+}
+
+// InsertSortedFunc works like InsertSorted, but uses a custom comparison function.
+// see BinarySearchFunc for more info
+func InsertSortedFunc[E any](cmp func(E, E) int, s []E, v E) []E {
+	pos, _ := BinarySearchFunc(cmp, v, s)
+	return Insert(s, pos, v)
+}
+
+// InsertSortedKey accepts a measuring key and calls InsertSortedFunc
+func InsertSortedKey[E any, O rules.Ordered](key func(E) O, s []E, v E) []E {
+	k := Key[E, O](key)
+	return InsertSortedFunc(k.Cmp, s, v)
+}
+
+// UnionSorted merges two slices, sorted in increasing order, into a new sorted
+// slice containing every element of either, once. It is O(len(a) + len(b)).
+func UnionSorted[E rules.Ordered](a, b []E) []E {
+	out := make([]E, 0, len(a)+len(b))
+	push := func(e E) {
+		if len(out) == 0 || out[len(out)-1] != e {
+			out = append(out, e)
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			push(b[j])
+			j++
+		} else {
+			push(a[i])
+			i++
+		}
+	}
+	for _, e := range a[i:] {
+		push(e)
+	}
+	for _, e := range b[j:] {
+		push(e)
+	}
+	return out
+}
+
+// IntersectionSorted walks two slices, sorted in increasing order, and returns a
+// new sorted slice containing every element found in both, once.
+// It is O(len(a) + len(b)).
+func IntersectionSorted[E rules.Ordered](a, b []E) []E {
+	out := []E{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			if len(out) == 0 || out[len(out)-1] != a[i] {
+				out = append(out, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// DifferenceSorted walks two slices, sorted in increasing order, and returns a
+// new sorted slice containing every element of a that is not in b, once.
+// It is O(len(a) + len(b)).
+func DifferenceSorted[E rules.Ordered](a, b []E) []E {
+	out := []E{}
+	j := 0
+	for _, e := range a {
+		for j < len(b) && b[j] < e {
+			j++
+		}
+		if j < len(b) && b[j] == e {
+			continue
+		}
+		if len(out) == 0 || out[len(out)-1] != e {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
 func search(n int, f func(int) bool) int {
 	// Define f(-1) == false and f(n) == true.
 	// Invariant: f(i-1) == false, f(j) == true.
@@ -7,6 +7,7 @@ package slices
 import (
 	"math/rand"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -200,3 +201,25 @@ func BenchmarkSortFuncStructs(b *testing.B) {
 		SortFunc(lessFunc, ss)
 	}
 }
+
+// These benchmarks should scale linearly with the size of their inputs
+func benchmarkSetSorted(b *testing.B, f func(a, b []int) []int) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		x, y := makeRandomInts(n), makeRandomInts(n/2)
+		Sort(x)
+		Sort(y)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f(x, y)
+			}
+		})
+	}
+}
+
+func BenchmarkIntersectionSorted(b *testing.B) {
+	benchmarkSetSorted(b, IntersectionSorted[int])
+}
+
+func BenchmarkDifferenceSorted(b *testing.B) {
+	benchmarkSetSorted(b, DifferenceSorted[int])
+}
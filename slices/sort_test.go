@@ -174,6 +174,110 @@ func TestStability(t *testing.T) {
 	}
 }
 
+func TestIsSortedUntil(t *testing.T) {
+	tests := []struct {
+		x    []int
+		want int
+	}{
+		{nil, 0},
+		{[]int{1}, 1},
+		{[]int{1, 2, 2, 3}, 4},
+		{[]int{4, 3, 2, 1}, 1},
+		{[]int{1, 2, 5, 3, 4}, 3},
+		{[]int{1, 2, 3, 0}, 3},
+	}
+	for _, test := range tests {
+		if got := IsSortedUntil(test.x); got != test.want {
+			t.Errorf("IsSortedUntil(%v) = %d, want %d", test.x, got, test.want)
+		}
+		if got, want := IsSortedUntil(test.x) == len(test.x), IsSorted(test.x); got != want {
+			t.Errorf("IsSortedUntil(%v) == len = %t, but IsSorted = %t", test.x, got, want)
+		}
+	}
+}
+
+func TestSortCmp(t *testing.T) {
+	cmp := func(a, b int) int { return a - b }
+	for i := 0; i < 10; i++ {
+		data := make([]int, 500)
+		for j := range data {
+			data[j] = rand.Intn(100)
+		}
+		viaLess, viaCmp, viaStable := Clone(data), Clone(data), Clone(data)
+		SortFunc(func(a, b int) bool { return a < b }, viaLess)
+		SortCmp(cmp, viaCmp)
+		SortStableCmp(cmp, viaStable)
+		if !Equal(viaCmp, viaLess) {
+			t.Errorf("SortCmp gave %v, want %v", viaCmp, viaLess)
+		}
+		if !Equal(viaStable, viaLess) {
+			t.Errorf("SortStableCmp gave %v, want %v", viaStable, viaLess)
+		}
+		for _, e := range data[:10] {
+			pos, found := BinarySearchFunc(cmp, e, viaCmp)
+			if want, _ := BinarySearch(e, viaLess); !found || pos != want {
+				t.Errorf("BinarySearchFunc(cmp, %d, SortCmp(cmp, data)) = %d, %t, want %d, true", e, pos, found, want)
+			}
+		}
+	}
+
+	n, m := 10000, 100
+	if testing.Short() {
+		n, m = 1000, 10
+	}
+	data := make(intPairs, n)
+	for i := range data {
+		data[i].a = rand.Intn(m)
+	}
+	data.initB()
+	SortStableCmp(func(x, y intPair) int { return x.a - y.a }, data)
+	if !IsSortedFunc(intPairLess, data) {
+		t.Errorf("SortStableCmp didn't sort %d ints", n)
+	}
+	if !data.inOrder() {
+		t.Errorf("SortStableCmp wasn't stable on %d ints", n)
+	}
+}
+
+func TestCompareChain(t *testing.T) {
+	type person struct {
+		last, first string
+		age         int
+	}
+	people := []person{
+		{"smith", "joe", 40},
+		{"jones", "amy", 30},
+		{"smith", "ann", 25},
+		{"jones", "amy", 20},
+		{"brown", "zoe", 50},
+	}
+	byLast := Key[person, string](func(p person) string { return p.last }).Cmp
+	byFirst := Key[person, string](func(p person) string { return p.first }).Cmp
+	byAge := Key[person, int](func(p person) int { return p.age }).Cmp
+
+	SortCmp(CompareChain(byLast, byFirst, byAge), people)
+	want := []person{
+		{"brown", "zoe", 50},
+		{"jones", "amy", 20},
+		{"jones", "amy", 30},
+		{"smith", "ann", 25},
+		{"smith", "joe", 40},
+	}
+	if !Equal(people, want) {
+		t.Errorf("sorting by last, first, then age gave %v, want %v", people, want)
+	}
+
+	if c := CompareChain[person]()(people[0], people[1]); c != 0 {
+		t.Errorf("CompareChain()(%v, %v) = %d, want 0", people[0], people[1], c)
+	}
+	if c := CompareChain(byLast, byFirst)(people[1], people[2]); c != 0 {
+		t.Errorf("CompareChain(byLast, byFirst)(%v, %v) = %d, want 0", people[1], people[2], c)
+	}
+	if c := CompareChain(byAge, byLast)(people[0], people[1]); c <= 0 {
+		t.Errorf("CompareChain(byAge, byLast)(%v, %v) = %d, want > 0", people[0], people[1], c)
+	}
+}
+
 func TestBinarySearch(t *testing.T) {
 	str1 := []string{"foo"}
 	str2 := []string{"ab", "ca"}
@@ -285,3 +389,199 @@ func TestBinarySearchInts(t *testing.T) {
 		})
 	}
 }
+
+func TestBounds(t *testing.T) {
+	data := []int{20, 30, 30, 30, 40, 50}
+	tests := []struct {
+		target       int
+		lower, upper int
+	}{
+		{10, 0, 0},
+		{20, 0, 1},
+		{25, 1, 1},
+		{30, 1, 4},
+		{50, 5, 6},
+		{60, 6, 6},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.target), func(t *testing.T) {
+			if got := LowerBound(tt.target, data); got != tt.lower {
+				t.Errorf("LowerBound got %v, want %v", got, tt.lower)
+			}
+			if got := UpperBound(tt.target, data); got != tt.upper {
+				t.Errorf("UpperBound got %v, want %v", got, tt.upper)
+			}
+		})
+	}
+	if lower, upper := LowerBound(1, []int{}), UpperBound(1, []int{}); lower != 0 || upper != 0 {
+		t.Errorf("bounds of empty slice got (%v, %v), want (0, 0)", lower, upper)
+	}
+}
+
+func TestEqualRange(t *testing.T) {
+	strRepeats := []string{"ba", "ca", "da", "da", "da", "ka", "ma", "ma", "ta"}
+	strSame := []string{"xx", "xx", "xx"}
+
+	tests := []struct {
+		data   []string
+		target string
+		lo, hi int
+	}{
+		{[]string{}, "foo", 0, 0},
+		{strRepeats, "aa", 0, 0},
+		{strRepeats, "ba", 0, 1},
+		{strRepeats, "da", 2, 5},
+		{strRepeats, "db", 5, 5},
+		{strRepeats, "ma", 6, 8},
+		{strRepeats, "ta", 8, 9},
+		{strRepeats, "zz", 9, 9},
+		{strSame, "xx", 0, 3},
+		{strSame, "ab", 0, 0},
+		{strSame, "zz", 3, 3},
+	}
+	first := func(s string) byte { return s[0] }
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if lo, hi := EqualRange(tt.target, tt.data); lo != tt.lo || hi != tt.hi {
+				t.Errorf("EqualRange got (%v, %v), want (%v, %v)", lo, hi, tt.lo, tt.hi)
+			}
+			if lo, hi := EqualRangeFunc(strings.Compare, tt.target, tt.data); lo != tt.lo || hi != tt.hi {
+				t.Errorf("EqualRangeFunc got (%v, %v), want (%v, %v)", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+
+	if lo, hi := EqualRangeKey(first, "mz", strRepeats); lo != 6 || hi != 8 {
+		t.Errorf("EqualRangeKey got (%v, %v), want (6, 8)", lo, hi)
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	tests := []struct {
+		data  []int
+		value int
+		want  []int
+	}{
+		{data: []int{}, value: 5, want: []int{5}},
+		{data: []int{20, 30, 40}, value: 10, want: []int{10, 20, 30, 40}},
+		{data: []int{20, 30, 40}, value: 35, want: []int{20, 30, 35, 40}},
+		{data: []int{20, 30, 40}, value: 50, want: []int{20, 30, 40, 50}},
+		{data: []int{20, 30, 30, 40}, value: 30, want: []int{20, 30, 30, 30, 40}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.value), func(t *testing.T) {
+			{
+				have := InsertSorted(Clone(tt.data), tt.value)
+				if !Equal(have, tt.want) || !IsSorted(have) {
+					t.Errorf("InsertSorted got %v, want %v", have, tt.want)
+				}
+			}
+
+			{
+				have := InsertSortedFunc(cmp[int], Clone(tt.data), tt.value)
+				if !Equal(have, tt.want) || !IsSorted(have) {
+					t.Errorf("InsertSortedFunc got %v, want %v", have, tt.want)
+				}
+			}
+
+			{
+				negate := func(i int) int { return -i }
+				data := Cast(negate, tt.data)
+				have := InsertSortedKey(negate, data, -tt.value)
+				if want := Cast(negate, tt.want); !Equal(have, want) || !IsSortedKey(negate, have) {
+					t.Errorf("InsertSortedKey got %v, want %v", have, want)
+				}
+			}
+		})
+	}
+
+	t.Run("running buffer", func(t *testing.T) {
+		var buf []int
+		for _, e := range rand.Perm(100) {
+			buf = InsertSorted(buf, e)
+			if !IsSorted(buf) {
+				t.Fatalf("InsertSorted left %v unsorted", buf)
+			}
+		}
+		if !Equal(buf, Upton[int](100)) {
+			t.Errorf("InsertSorted got %v, want %v", buf, Upton[int](100))
+		}
+	})
+}
+
+// randomInts works like makeRandomInts, but draws from r instead of reseeding
+// the global source, so successive calls differ and other tests are unaffected
+func randomInts(r *rand.Rand, n int) []int {
+	ints := make([]int, n)
+	for i := range ints {
+		ints[i] = r.Intn(n)
+	}
+	return ints
+}
+
+func TestUnionSorted(t *testing.T) {
+	tests := []struct {
+		a, b, want []int
+	}{
+		{nil, nil, []int{}},
+		{[]int{1, 2}, nil, []int{1, 2}},
+		{nil, []int{1, 1, 2}, []int{1, 2}},
+		{[]int{1, 3, 5}, []int{2, 3, 4, 6}, []int{1, 2, 3, 4, 5, 6}},
+		{[]int{1, 1, 2, 2}, []int{2, 2, 3, 3}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := UnionSorted(tt.a, tt.b); !Equal(got, tt.want) {
+			t.Errorf("UnionSorted(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		r := rand.New(rand.NewSource(int64(i)))
+		a, b := randomInts(r, r.Intn(100)), randomInts(r, r.Intn(100))
+		Sort(a)
+		Sort(b)
+		got := UnionSorted(a, b)
+		if !IsSorted(got) {
+			t.Errorf("UnionSorted(%v, %v) = %v is not sorted", a, b, got)
+		}
+		if want := Compacted(Sorted(Chain(a, b))); !Equal(got, want) {
+			t.Errorf("UnionSorted(%v, %v) = %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func TestIntersectionDifferenceSorted(t *testing.T) {
+	tests := []struct {
+		a, b, and, not []int
+	}{
+		{nil, nil, []int{}, []int{}},
+		{[]int{1, 2}, nil, []int{}, []int{1, 2}},
+		{nil, []int{1, 2}, []int{}, []int{}},
+		{[]int{1, 2, 3, 5}, []int{2, 3, 4}, []int{2, 3}, []int{1, 5}},
+		{[]int{1, 1, 2, 2, 3}, []int{2, 2, 4}, []int{2}, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		if got := IntersectionSorted(tt.a, tt.b); !Equal(got, tt.and) {
+			t.Errorf("IntersectionSorted(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.and)
+		}
+		if got := DifferenceSorted(tt.a, tt.b); !Equal(got, tt.not) {
+			t.Errorf("DifferenceSorted(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.not)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		r := rand.New(rand.NewSource(int64(i)))
+		a := randomInts(r, r.Intn(100))
+		b := Cast(func(e int) int { return e + i }, randomInts(r, r.Intn(100)))
+		and := Compacted(Sorted(FilterFunc(func(e int) bool { return Contains(b, e) }, a)))
+		not := Compacted(Sorted(FilterFunc(func(e int) bool { return !Contains(b, e) }, a)))
+		Sort(a)
+		Sort(b)
+		if got := IntersectionSorted(a, b); !Equal(got, and) {
+			t.Errorf("IntersectionSorted(%v, %v) = %v, want %v", a, b, got, and)
+		}
+		if got := DifferenceSorted(a, b); !Equal(got, not) {
+			t.Errorf("DifferenceSorted(%v, %v) = %v, want %v", a, b, got, not)
+		}
+	}
+}
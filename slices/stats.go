@@ -0,0 +1,65 @@
+package slices
+
+import "github.com/kendfss/rules"
+
+// Stats accumulates the count, extrema, mean, and variance of a numeric stream
+// without buffering it. The mean and variance are maintained with Welford's
+// algorithm, which avoids the cancellation suffered by the naive sum of squares.
+// The zero value is an empty accumulator, ready for use.
+type Stats[N rules.Real] struct {
+	count    int
+	min, max N
+	mean, m2 float64
+}
+
+// NewStats returns an accumulator which has already been fed every element of s
+func NewStats[N rules.Real](s []N) *Stats[N] {
+	out := new(Stats[N])
+	for _, e := range s {
+		out.Add(e)
+	}
+	return out
+}
+
+// Stats.Add feeds a value to the accumulator
+func (s *Stats[N]) Add(v N) {
+	s.count++
+	if s.count == 1 || v < s.min {
+		s.min = v
+	}
+	if s.count == 1 || v > s.max {
+		s.max = v
+	}
+	delta := float64(v) - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (float64(v) - s.mean)
+}
+
+// Stats.Count returns the number of values fed to the accumulator
+func (s *Stats[N]) Count() int {
+	return s.count
+}
+
+// Stats.Min returns the smallest value fed to the accumulator, or zero if it is empty
+func (s *Stats[N]) Min() N {
+	return s.min
+}
+
+// Stats.Max returns the largest value fed to the accumulator, or zero if it is empty
+func (s *Stats[N]) Max() N {
+	return s.max
+}
+
+// Stats.Mean returns the arithmetic mean of the values fed to the accumulator, or zero if it is empty
+func (s *Stats[N]) Mean() float64 {
+	return s.mean
+}
+
+// Stats.Variance returns the population variance of the values fed to the accumulator,
+// or zero if it is empty
+func (s *Stats[N]) Variance() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.count)
+}